@@ -22,15 +22,16 @@ import (
 // If the API call fails or the status code is not 200, the function returns an error with the corresponding message.
 
 func (c *Client) GetBalance() (res ResponseBalance, err error) {
+	generation := c.balanceGeneration()
 	url, _ := url.Parse(fmt.Sprintf("%s/api/v2/balance", c.EnvApi))
 	jsonBody, _ := json.Marshal(map[string]string{"account": c.VirtualAccount})
-	signature := fmt.Sprintf("%s", GenerateSignature(string(jsonBody), "POST", *c))
+	signature := fmt.Sprintf("%s", c.signature(jsonBody))
 	api, err := c.CallApi(url, signature, jsonBody)
 	if err != nil {
 		return res, err
 	}
 
-	err = json.Unmarshal(api, &res)
+	err = c.decodeResponse(url, api, &res)
 	if err != nil {
 		return res, err
 	}
@@ -39,5 +40,7 @@ func (c *Client) GetBalance() (res ResponseBalance, err error) {
 		return res, fmt.Errorf("%s\n", res.Message)
 	}
 
+	c.setBalance(res.Data.MerchantBalance, generation)
+
 	return
 }
@@ -0,0 +1,146 @@
+package ipaymu_go_api
+
+import (
+	"context"
+	"sync"
+)
+
+// BulkCheckResult is the status of one transaction checked by BulkChecker.
+//
+// Transaction is set when the transaction was found through HistoryTransaction, Check when
+// it was looked up with CheckTransaction. Err is set when neither succeeded.
+type BulkCheckResult struct {
+	TransactionID int
+	Status        PaymentStatus
+	Transaction   *Transaction
+	Check         *ResponseCheck
+	Err           error
+}
+
+// BulkChecker checks the status of many transactions at once.
+//
+// IDs are first looked up in chunks of ChunkSize through the BulkId filter of HistoryTransaction.
+// IDs missing from the history, or from chunks that failed, are checked one by one with
+// CheckTransaction. At most Workers calls run concurrently.
+type BulkChecker struct {
+	Client    ClientApi
+	ChunkSize int
+	Workers   int
+}
+
+// NewBulkChecker creates a BulkChecker using chunks of 20, the history limit, and 8 workers.
+func NewBulkChecker(client ClientApi) *BulkChecker {
+	return &BulkChecker{
+		Client:    client,
+		ChunkSize: 20,
+		Workers:   8,
+	}
+}
+
+// Check returns the status of every transaction in ids, in the same order.
+// When ctx is done, the transactions not checked yet get the context error.
+func (b *BulkChecker) Check(ctx context.Context, ids []int) []BulkCheckResult {
+	chunkSize := b.ChunkSize
+	if chunkSize <= 0 || chunkSize > MaxHistoryLimit {
+		chunkSize = MaxHistoryLimit
+	}
+
+	var mu sync.Mutex
+	found := make(map[int]Transaction, len(ids))
+	var chunks [][]int
+	for start := 0; start < len(ids); start += chunkSize {
+		end := start + chunkSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunks = append(chunks, ids[start:end])
+	}
+	b.run(ctx, len(chunks), func(i int) {
+		trx, err := b.history(chunks[i])
+		if err != nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, t := range trx {
+			found[t.TransactionId.Int()] = t
+		}
+	})
+
+	results := make([]BulkCheckResult, len(ids))
+	var missing []int
+	for i, id := range ids {
+		results[i].TransactionID = id
+		if t, ok := found[id]; ok {
+			results[i].Transaction = &t
+			results[i].Status = t.Status
+			continue
+		}
+		missing = append(missing, i)
+	}
+
+	b.run(ctx, len(missing), func(i int) {
+		result := &results[missing[i]]
+		check, err := b.Client.CheckTransaction(result.TransactionID)
+		if err != nil {
+			result.Err = err
+			return
+		}
+		result.Check = &check
+		result.Status = check.Data.Status
+	})
+
+	if err := ctx.Err(); err != nil {
+		for i := range results {
+			if results[i].Transaction == nil && results[i].Check == nil && results[i].Err == nil {
+				results[i].Err = err
+			}
+		}
+	}
+	return results
+}
+
+// history looks up a chunk of IDs with the BulkId filter.
+func (b *BulkChecker) history(ids []int) ([]Transaction, error) {
+	request, err := NewHistoryQuery().Transactions(ids...).Limit(len(ids)).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := b.Client.HistoryTransaction(request)
+	if err != nil {
+		return nil, err
+	}
+	return res.Data.Transaction, nil
+}
+
+// run calls fn for 0 to n-1 on at most b.Workers goroutines, stopping early when ctx is done.
+func (b *BulkChecker) run(ctx context.Context, n int, fn func(i int)) {
+	workers := b.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+
+dispatch:
+	for i := 0; i < n; i++ {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
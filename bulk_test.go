@@ -0,0 +1,79 @@
+package ipaymu_go_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBulkChecker_Check(t *testing.T) {
+	var historyCalls, checkCalls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/history":
+			atomic.AddInt32(&historyCalls, 1)
+			var request RequestTransactionHistory
+			json.NewDecoder(r.Body).Decode(&request)
+			var trx []string
+			for _, v := range strings.Split(*request.BulkId, ",") {
+				id, _ := strconv.Atoi(v)
+				// IDs divisible by 7 are missing from the history.
+				if id%7 != 0 {
+					trx = append(trx, fmt.Sprintf(`{"TransactionId":%d,"Status":1}`, id))
+				}
+			}
+			fmt.Fprintf(w, `{"Status":200,"Data":{"Transaction":[%s]}}`, strings.Join(trx, ","))
+		case "/api/v2/transaction":
+			atomic.AddInt32(&checkCalls, 1)
+			var request map[string]int
+			json.NewDecoder(r.Body).Decode(&request)
+			if request["transactionId"] == 14 {
+				fmt.Fprint(w, `{"Status":404,"Message":"transaction not found"}`)
+				return
+			}
+			fmt.Fprintf(w, `{"Status":200,"Data":{"TransactionId":%d,"Status":-2}}`, request["transactionId"])
+		}
+	}))
+	defer srv.Close()
+
+	cl := &Client{}
+	cl.AssignCredential("api-key", "1179000899", EnvironmentType(srv.URL))
+
+	ids := make([]int, 45)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	results := NewBulkChecker(cl).Check(context.Background(), ids)
+
+	if historyCalls != 3 {
+		t.Errorf("HistoryTransaction called %d times, want 3", historyCalls)
+	}
+	if checkCalls != 6 {
+		t.Errorf("CheckTransaction called %d times, want 6", checkCalls)
+	}
+	for i, result := range results {
+		if result.TransactionID != ids[i] {
+			t.Fatalf("result %d is for transaction %d, want %d", i, result.TransactionID, ids[i])
+		}
+		switch {
+		case result.TransactionID == 14:
+			if result.Err == nil {
+				t.Errorf("result for 14 has no error")
+			}
+		case result.TransactionID%7 == 0:
+			if result.Check == nil || result.Status != Expired {
+				t.Errorf("result for %d = %+v, want checked and expired", result.TransactionID, result)
+			}
+		default:
+			if result.Transaction == nil || result.Status != Success {
+				t.Errorf("result for %d = %+v, want found in history and successful", result.TransactionID, result)
+			}
+		}
+	}
+}
@@ -0,0 +1,405 @@
+package ipaymu_go_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CallbackEvent is passed to a CallbackFunc for every callback iPaymu posts to the notify URL.
+type CallbackEvent struct {
+	Callback RequestCallBack
+	Status   PaymentStatus
+
+	// Transaction is the result of CheckTransaction when the callback was verified or enriched.
+	Transaction *ResponseCheck
+	// InvalidTransition is set when Tracker rejected the move to Status from the status
+	// previously seen for the transaction.
+	InvalidTransition error
+
+	// FirstPaid is set on a settled callback when Dedup shows that no paid callback was handled
+	// for the transaction before, so the settlement also stands for the payment itself.
+	FirstPaid bool
+
+	// Verified is set when the callback matched Transaction.
+	// Mismatch is set in VerifyFlag mode when the callback does not match Transaction.
+	Verified bool
+	Mismatch *CallbackRejection
+}
+
+// CallbackFunc handles a decoded callback. Returning an error makes the handler reply with
+// 500 Internal Server Error so that iPaymu retries the callback later.
+type CallbackFunc func(r *http.Request, event *CallbackEvent) error
+
+// CallbackHandler is an http.Handler for the notifyUrl of payment requests.
+//
+// It accepts form-encoded and JSON callbacks, decodes them into a RequestCallBack,
+// invokes Func and replies with the response iPaymu expects.
+type CallbackHandler struct {
+	Func CallbackFunc
+
+	// Verify sets how callbacks are checked against CheckTransaction on Client before Func runs.
+	Verify VerifyMode
+	Client ClientApi
+
+	// Enrich, when set, fetches the transaction with CheckTransaction on Client, stores it in
+	// CallbackEvent.Transaction and fills the callback fields iPaymu left empty from it.
+	// Cache, when set, keeps fetched transactions that match their callback for verification and enrichment.
+	Enrich bool
+	Cache  *TransactionCache
+
+	// AllowedNetworks, when not empty, restricts callbacks to these source networks.
+	// TrustedProxyHeader names a header such as X-Forwarded-For to read the source address from;
+	// only set it behind a proxy that sets the header itself.
+	AllowedNetworks    []*net.IPNet
+	TrustedProxyHeader string
+
+	// MaxAge, when positive, rejects callbacks whose latest transaction timestamp is older.
+	// SettlementWindow is added to MaxAge for settled callbacks, which can arrive days after
+	// paid_at; it defaults to 7 days. Now returns the current time and defaults to time.Now.
+	MaxAge           time.Duration
+	SettlementWindow time.Duration
+	Now              func() time.Time
+
+	// Tracker, when set, validates the status of every callback against the previous one for
+	// the same transaction and flags impossible moves in CallbackEvent.InvalidTransition.
+	Tracker *StatusTracker
+
+	// Dedup, when set, makes sure Func runs at most once per transaction ID and status, with
+	// settlement counting as a status of its own. A settled callback also claims the paid
+	// transition and sets CallbackEvent.FirstPaid when that claim succeeds.
+	// Duplicates are acknowledged with 200 OK without calling Func. An invocation that
+	// returns an error releases its claims, so the retry from iPaymu is handled again.
+	Dedup DedupStore
+
+	// Publisher, when set, receives every verified callback as a PaymentEvent once Func succeeds.
+	// Only callbacks that matched CheckTransaction are published, so Verify must be set as well.
+	// Events are published in the background, so a slow or failing publisher neither delays the
+	// reply to iPaymu nor makes it retry the callback and run Func again. Events for the same
+	// transaction are published one at a time, in the order their callbacks were handled.
+	// PublishTimeout bounds each publish and defaults to one minute; OnPublishError is called
+	// when a publish fails.
+	Publisher      Publisher
+	PublishTimeout time.Duration
+	OnPublishError func(event PaymentEvent, err error)
+
+	// OnReject, when set, is called for every callback that is rejected before reaching Func.
+	OnReject func(r *http.Request, rejection *CallbackRejection)
+
+	publishing sync.WaitGroup
+	publishMu  sync.Mutex
+	// publishTail holds, per transaction ID, a channel closed when its latest publish is done.
+	publishTail map[int]chan struct{}
+}
+
+// NewCallbackHandler creates a new CallbackHandler that invokes fn for every callback.
+func NewCallbackHandler(fn CallbackFunc) *CallbackHandler {
+	return &CallbackHandler{
+		Func: fn,
+	}
+}
+
+// ServeHTTP implements http.Handler.
+//
+// Only POST requests are accepted. Rejected callbacks are answered with the status of their
+// CallbackRejection, callbacks whose Func returns an error with 500 Internal Server Error,
+// and all others with 200 OK.
+func (h *CallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeCallbackResponse(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if rejection := h.checkSource(r); rejection != nil {
+		h.reject(w, r, rejection)
+		return
+	}
+
+	cb, err := ParseCallback(r)
+	if err != nil {
+		h.reject(w, r, &CallbackRejection{Reason: RejectMalformed, Err: err})
+		return
+	}
+	if rejection := h.checkAge(cb); rejection != nil {
+		h.reject(w, r, rejection)
+		return
+	}
+
+	event := &CallbackEvent{
+		Callback: cb,
+		Status:   cb.PaymentStatus(),
+	}
+	if rejection := h.verify(event); rejection != nil {
+		h.reject(w, r, rejection)
+		return
+	}
+	if rejection := h.enrich(event); rejection != nil {
+		h.reject(w, r, rejection)
+		return
+	}
+
+	if h.Tracker != nil {
+		event.InvalidTransition = h.Tracker.Observe(cb.TrxID, event.Status)
+	}
+
+	var keys []string
+	if h.Dedup != nil {
+		key := DedupKey(cb)
+		claimed, err := h.Dedup.Claim(key)
+		if err != nil {
+			writeCallbackResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if !claimed {
+			writeCallbackResponse(w, http.StatusOK, "duplicate")
+			return
+		}
+		keys = append(keys, key)
+
+		if cb.Settled() {
+			paid := cb
+			paid.SettlementStatus = ""
+			paidKey := DedupKey(paid)
+			if event.FirstPaid, err = h.Dedup.Claim(paidKey); err != nil {
+				h.Dedup.Release(key)
+				writeCallbackResponse(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			if event.FirstPaid {
+				keys = append(keys, paidKey)
+			}
+		}
+	}
+
+	if err := h.handle(r, event); err != nil {
+		// A failed invocation does not count, so the retry from iPaymu can run Func again.
+		for _, key := range keys {
+			h.Dedup.Release(key)
+		}
+		writeCallbackResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.publish(event)
+	writeCallbackResponse(w, http.StatusOK, "success")
+}
+
+// handle runs Func.
+func (h *CallbackHandler) handle(r *http.Request, event *CallbackEvent) error {
+	if h.Func != nil {
+		return h.Func(r, event)
+	}
+	return nil
+}
+
+// defaultPublishTimeout bounds a background publish when PublishTimeout is not set.
+const defaultPublishTimeout = time.Minute
+
+// publish hands the event to Publisher in the background, after the events published before for
+// the same transaction. Callbacks that were not verified are not published.
+func (h *CallbackHandler) publish(event *CallbackEvent) {
+	if h.Publisher == nil || !event.Verified {
+		return
+	}
+
+	timeout := h.PublishTimeout
+	if timeout <= 0 {
+		timeout = defaultPublishTimeout
+	}
+	paymentEvent := NewPaymentEvent(event)
+
+	trxID := event.Callback.TrxID
+	done := make(chan struct{})
+	h.publishMu.Lock()
+	if h.publishTail == nil {
+		h.publishTail = make(map[int]chan struct{})
+	}
+	previous := h.publishTail[trxID]
+	h.publishTail[trxID] = done
+	h.publishMu.Unlock()
+
+	h.publishing.Add(1)
+	go func() {
+		defer h.publishing.Done()
+		defer func() {
+			close(done)
+			h.publishMu.Lock()
+			if h.publishTail[trxID] == done {
+				delete(h.publishTail, trxID)
+			}
+			h.publishMu.Unlock()
+		}()
+		if previous != nil {
+			<-previous
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if err := h.Publisher.Publish(ctx, paymentEvent); err != nil && h.OnPublishError != nil {
+			h.OnPublishError(paymentEvent, err)
+		}
+	}()
+}
+
+// Wait blocks until the events being published in the background are done, for example
+// before the server shuts down.
+func (h *CallbackHandler) Wait() {
+	h.publishing.Wait()
+}
+
+func (h *CallbackHandler) reject(w http.ResponseWriter, r *http.Request, rejection *CallbackRejection) {
+	if h.OnReject != nil {
+		h.OnReject(r, rejection)
+	}
+	writeCallbackResponse(w, rejection.StatusCode(), rejection.Error())
+}
+
+// maxCallbackBody caps the size of a JSON callback body. Form bodies are capped by http.Request.ParseForm.
+const maxCallbackBody = 1 << 20
+
+// ParseCallback decodes the callback in the body of r.
+//
+// Requests with a JSON content type are decoded as JSON, anything else is parsed as a form.
+// JSON bodies larger than 1 MB are rejected.
+func ParseCallback(r *http.Request) (cb RequestCallBack, err error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/json" {
+		r.Body = http.MaxBytesReader(nil, r.Body, maxCallbackBody)
+		if err = json.NewDecoder(r.Body).Decode(&cb); err != nil {
+			return cb, fmt.Errorf("invalid callback body: %w", err)
+		}
+		return
+	}
+
+	if err = r.ParseForm(); err != nil {
+		return cb, fmt.Errorf("invalid callback body: %w", err)
+	}
+
+	fields := make(map[string]json.RawMessage, len(r.PostForm))
+	for key, values := range r.PostForm {
+		fields[key], _ = json.Marshal(values[0])
+	}
+	if err = cb.decodeFields(fields); err != nil {
+		return cb, fmt.Errorf("invalid callback body: %w", err)
+	}
+
+	return
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting numbers and booleans sent as strings.
+func (c *RequestCallBack) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	return c.decodeFields(fields)
+}
+
+// decodeFields fills the callback from its raw fields, keeping unknown fields in Extra.
+func (c *RequestCallBack) decodeFields(fields map[string]json.RawMessage) (err error) {
+	*c = RequestCallBack{}
+	for key, raw := range fields {
+		var n int64
+		switch key {
+		case "trx_id":
+			n, err = flexInt(raw)
+			c.TrxID = int(n)
+		case "sid":
+			c.SID, err = flexString(raw)
+		case "reference_id":
+			c.ReferenceID, err = flexString(raw)
+		case "status":
+			c.Status, err = flexString(raw)
+		case "status_code":
+			n, err = flexInt(raw)
+			c.StatusCode = PaymentStatus(n)
+		case "sub_total":
+			err = c.SubTotal.UnmarshalJSON(raw)
+		case "total":
+			err = c.Total.UnmarshalJSON(raw)
+		case "amount":
+			err = c.Amount.UnmarshalJSON(raw)
+		case "fee":
+			err = c.Fee.UnmarshalJSON(raw)
+		case "paid_off":
+			err = c.PaidOff.UnmarshalJSON(raw)
+		case "created_at":
+			err = c.CreatedAt.UnmarshalJSON(raw)
+		case "expired_at":
+			err = c.ExpiredAt.UnmarshalJSON(raw)
+		case "paid_at":
+			err = c.PaidAt.UnmarshalJSON(raw)
+		case "settlement_status":
+			c.SettlementStatus, err = flexString(raw)
+		case "transaction_status_code":
+			n, err = flexInt(raw)
+			c.TransactionStatusCode = int(n)
+		case "is_escrow":
+			c.IsEscrow, err = flexBool(raw)
+		case "system_notes":
+			c.SystemNotes, err = flexString(raw)
+		case "via":
+			c.Via, err = flexString(raw)
+		case "channel":
+			c.Channel, err = flexString(raw)
+		case "payment_no":
+			c.PaymentNo, err = flexString(raw)
+		case "buyer_name":
+			c.BuyerName, err = flexString(raw)
+		case "buyer_email":
+			c.BuyerEmail, err = flexString(raw)
+		case "buyer_phone":
+			c.BuyerPhone, err = flexString(raw)
+		case "additional_info":
+			c.AdditionalInfo = raw
+		case "url":
+			c.Url, err = flexString(raw)
+		case "va":
+			c.Va, err = flexString(raw)
+		default:
+			if c.Extra == nil {
+				c.Extra = make(map[string]json.RawMessage)
+			}
+			c.Extra[key] = raw
+		}
+		if err != nil {
+			return fmt.Errorf("field %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// PaymentStatus returns the status of the callback as a PaymentStatus.
+//
+// The status code is used when present; a zero status code falls back to the textual status,
+// since form callbacks may omit the code.
+func (c RequestCallBack) PaymentStatus() PaymentStatus {
+	if c.StatusCode == 0 {
+		if status, ok := statusText[strings.ToLower(c.Status)]; ok {
+			return status
+		}
+	}
+	return c.StatusCode
+}
+
+// Settled reports whether the callback is for a paid transaction whose funds are settled.
+func (c RequestCallBack) Settled() bool {
+	return c.PaymentStatus() == Success && strings.EqualFold(c.SettlementStatus, "settled")
+}
+
+func writeCallbackResponse(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"Status":  status,
+		"Message": message,
+	})
+}
@@ -0,0 +1,117 @@
+package ipaymu_go_api
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ParseNetworks parses CIDR notations such as "103.24.56.0/24" for CallbackHandler.AllowedNetworks.
+// A plain IP address is accepted as a single-host network.
+func ParseNetworks(cidrs ...string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid network %q", cidr)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %q: %w", cidr, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// checkSource rejects requests whose source address is outside h.AllowedNetworks.
+func (h *CallbackHandler) checkSource(r *http.Request) *CallbackRejection {
+	if len(h.AllowedNetworks) == 0 {
+		return nil
+	}
+
+	source := sourceIP(r, h.TrustedProxyHeader)
+	ip := net.ParseIP(source)
+	if ip == nil {
+		return &CallbackRejection{Reason: RejectSource, Err: fmt.Errorf("invalid source address %q", source)}
+	}
+	for _, network := range h.AllowedNetworks {
+		if network.Contains(ip) {
+			return nil
+		}
+	}
+	return &CallbackRejection{Reason: RejectSource, Err: fmt.Errorf("source address %s is not allowed", ip)}
+}
+
+// sourceIP returns the address the request came from. When header is set and present, the last
+// address in it is used, which is the one added by the trusted proxy in front of the server.
+func sourceIP(r *http.Request, header string) string {
+	if header != "" {
+		if v := r.Header.Get(header); v != "" {
+			parts := strings.Split(v, ",")
+			return strings.TrimSpace(parts[len(parts)-1])
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// defaultSettlementWindow is added to MaxAge for settled callbacks when SettlementWindow is not set.
+const defaultSettlementWindow = 7 * 24 * time.Hour
+
+// checkAge rejects callbacks whose latest transaction timestamp is older than h.MaxAge.
+//
+// The latest timestamp is the newest of created_at and paid_at, and of expired_at for expired
+// callbacks, whose expiry is the event they report. Settled callbacks do not report when the
+// funds were settled, which can be days after paid_at, so SettlementWindow is added to MaxAge for them.
+func (h *CallbackHandler) checkAge(cb RequestCallBack) *CallbackRejection {
+	if h.MaxAge <= 0 {
+		return nil
+	}
+
+	times := []NullTime{cb.CreatedAt, cb.PaidAt}
+	if cb.PaymentStatus() == Expired {
+		times = append(times, cb.ExpiredAt)
+	}
+	var latest time.Time
+	for _, t := range times {
+		if t.Valid && t.Time.After(latest) {
+			latest = t.Time
+		}
+	}
+	if latest.IsZero() {
+		return &CallbackRejection{Reason: RejectStale, Err: fmt.Errorf("callback has no transaction timestamp")}
+	}
+
+	maxAge := h.MaxAge
+	if strings.EqualFold(cb.SettlementStatus, "settled") {
+		window := h.SettlementWindow
+		if window <= 0 {
+			window = defaultSettlementWindow
+		}
+		maxAge += window
+	}
+
+	now := time.Now
+	if h.Now != nil {
+		now = h.Now
+	}
+	if age := now().Sub(latest); age > maxAge {
+		return &CallbackRejection{Reason: RejectStale, Err: fmt.Errorf("transaction timestamp %s is %s old", latest.Format(time.RFC3339), age.Round(time.Second))}
+	}
+	return nil
+}
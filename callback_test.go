@@ -0,0 +1,445 @@
+package ipaymu_go_api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCallbackHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		funcErr     error
+		wantCode    int
+		wantTrxID   int
+		wantStatus  PaymentStatus
+		wantRef     string
+	}{
+		{
+			name:        "form callback",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "trx_id=96748&status=berhasil&status_code=1&sid=abc-123&reference_id=trx-123",
+			wantCode:    http.StatusOK,
+			wantTrxID:   96748,
+			wantStatus:  Success,
+			wantRef:     "trx-123",
+		},
+		{
+			name:        "json callback",
+			contentType: "application/json",
+			body:        `{"trx_id":96748,"status":"pending","status_code":0,"sid":"abc-123","reference_id":"trx-123"}`,
+			wantCode:    http.StatusOK,
+			wantTrxID:   96748,
+			wantStatus:  Pending,
+			wantRef:     "trx-123",
+		},
+		{
+			name:        "status text without code",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "trx_id=96748&status=expired",
+			wantCode:    http.StatusOK,
+			wantTrxID:   96748,
+			wantStatus:  Expired,
+		},
+		{
+			name:        "malformed trx_id",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "trx_id=abc&status=berhasil&status_code=1",
+			wantCode:    http.StatusBadRequest,
+		},
+		{
+			name:        "oversized json callback",
+			contentType: "application/json",
+			body:        `{"trx_id":96748,"status_code":1,"note":"` + strings.Repeat("x", maxCallbackBody) + `"}`,
+			wantCode:    http.StatusBadRequest,
+		},
+		{
+			name:        "handler error",
+			contentType: "application/json",
+			body:        `{"trx_id":96748,"status":"berhasil","status_code":1}`,
+			funcErr:     errors.New("database down"),
+			wantCode:    http.StatusInternalServerError,
+			wantTrxID:   96748,
+			wantStatus:  Success,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *CallbackEvent
+			h := NewCallbackHandler(func(r *http.Request, event *CallbackEvent) error {
+				got = event
+				return tt.funcErr
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/notify", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("ServeHTTP() code = %v, want %v", rec.Code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusBadRequest {
+				if got != nil {
+					t.Errorf("ServeHTTP() invoked handler for malformed callback")
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("ServeHTTP() did not invoke handler")
+			}
+			if got.Callback.TrxID != tt.wantTrxID || got.Status != tt.wantStatus || got.Callback.ReferenceID != tt.wantRef {
+				t.Errorf("ServeHTTP() event = %+v, want trx %v status %v ref %q", got, tt.wantTrxID, tt.wantStatus, tt.wantRef)
+			}
+		})
+	}
+}
+
+func TestRequestCallBack_UnmarshalJSON(t *testing.T) {
+	body := `{
+		"trx_id": "96748",
+		"sid": "abc-123",
+		"reference_id": 123,
+		"status": "berhasil",
+		"status_code": "1",
+		"sub_total": "100000",
+		"total": 104000.00,
+		"amount": "100000",
+		"fee": "4000",
+		"paid_off": 100000,
+		"created_at": "2024-05-01 10:00:00",
+		"expired_at": "2024-05-02 10:00:00",
+		"paid_at": "0000-00-00 00:00:00",
+		"settlement_status": "settled",
+		"transaction_status_code": 1,
+		"is_escrow": "0",
+		"via": "va",
+		"channel": "bca",
+		"payment_no": "8800123456",
+		"buyer_name": "buyer",
+		"new_field": {"nested": true}
+	}`
+
+	var cb RequestCallBack
+	if err := cb.UnmarshalJSON([]byte(body)); err != nil {
+		t.Fatalf("UnmarshalJSON() error = %v", err)
+	}
+
+	if cb.TrxID != 96748 || cb.StatusCode != 1 || cb.ReferenceID != "123" {
+		t.Errorf("UnmarshalJSON() ids = %v %v %q", cb.TrxID, cb.StatusCode, cb.ReferenceID)
+	}
+	if cb.Amount != 100000 || cb.Fee != 4000 || cb.Total != 104000 {
+		t.Errorf("UnmarshalJSON() amounts = %v %v %v", cb.Amount, cb.Fee, cb.Total)
+	}
+	if !cb.CreatedAt.Valid || cb.CreatedAt.Time.Hour() != 10 || cb.CreatedAt.Time.Location() != jakarta {
+		t.Errorf("UnmarshalJSON() created_at = %+v", cb.CreatedAt)
+	}
+	if cb.PaidAt.Valid || cb.PaidAt.Raw != "0000-00-00 00:00:00" {
+		t.Errorf("UnmarshalJSON() paid_at = %+v, want invalid with raw value", cb.PaidAt)
+	}
+	if cb.IsEscrow {
+		t.Errorf("UnmarshalJSON() is_escrow = true, want false")
+	}
+	if string(cb.Extra["new_field"]) != `{"nested": true}` {
+		t.Errorf("UnmarshalJSON() extra = %s", cb.Extra)
+	}
+}
+
+func TestCallbackHandler_Verify(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			TransactionID int `json:"transactionId"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		// Transaction 96749 is paid but not settled yet.
+		status := Success
+		if request.TransactionID == 96749 {
+			status = SuccessUnsettle
+		}
+		fmt.Fprintf(w, `{"Status":200,"Data":{"TransactionId":%d,"ReferenceId":"trx-123","Amount":100000,"Status":%d},"Message":"success"}`, request.TransactionID, status)
+	}))
+	defer srv.Close()
+
+	cl := &Client{}
+	cl.AssignCredential("api-key", "1179000899", EnvironmentType(srv.URL))
+
+	tests := []struct {
+		name         string
+		mode         VerifyMode
+		body         string
+		wantCode     int
+		wantCalled   bool
+		wantMismatch bool
+	}{
+		{
+			name:       "matching callback",
+			mode:       VerifyReject,
+			body:       "trx_id=96748&status=berhasil&status_code=1&reference_id=trx-123&amount=100000",
+			wantCode:   http.StatusOK,
+			wantCalled: true,
+		},
+		{
+			name:       "paid callback for unsettled transaction",
+			mode:       VerifyReject,
+			body:       "trx_id=96749&status=berhasil&reference_id=trx-123&amount=100000",
+			wantCode:   http.StatusOK,
+			wantCalled: true,
+		},
+		{
+			name:       "pending callback for paid transaction rejected",
+			mode:       VerifyReject,
+			body:       "trx_id=96749&status=pending&status_code=0&reference_id=trx-123&amount=100000",
+			wantCode:   http.StatusBadRequest,
+			wantCalled: false,
+		},
+		{
+			name:       "forged status rejected",
+			mode:       VerifyReject,
+			body:       "trx_id=96748&status=berhasil&status_code=1&reference_id=trx-123&amount=1000",
+			wantCode:   http.StatusBadRequest,
+			wantCalled: false,
+		},
+		{
+			name:         "forged status flagged",
+			mode:         VerifyFlag,
+			body:         "trx_id=96748&status=berhasil&status_code=1&reference_id=other&amount=100000",
+			wantCode:     http.StatusOK,
+			wantCalled:   true,
+			wantMismatch: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *CallbackEvent
+			var rejected *CallbackRejection
+			h := NewCallbackHandler(func(r *http.Request, event *CallbackEvent) error {
+				got = event
+				return nil
+			})
+			h.Verify = tt.mode
+			h.Client = cl
+			h.OnReject = func(r *http.Request, rejection *CallbackRejection) {
+				rejected = rejection
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/notify", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("ServeHTTP() code = %v, want %v", rec.Code, tt.wantCode)
+			}
+			if (got != nil) != tt.wantCalled {
+				t.Fatalf("ServeHTTP() called handler = %v, want %v", got != nil, tt.wantCalled)
+			}
+			if !tt.wantCalled && (rejected == nil || rejected.Reason != RejectMismatch) {
+				t.Errorf("ServeHTTP() rejection = %v, want %v", rejected, RejectMismatch)
+			}
+			if got != nil && (got.Mismatch != nil) != tt.wantMismatch {
+				t.Errorf("ServeHTTP() mismatch = %v, want %v", got.Mismatch, tt.wantMismatch)
+			}
+		})
+	}
+}
+
+func TestCallbackHandler_Guard(t *testing.T) {
+	networks, err := ParseNetworks("103.10.128.0/24", "10.0.0.5")
+	if err != nil {
+		t.Fatalf("ParseNetworks() error = %v", err)
+	}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, jakarta)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		body       string
+		wantCode   int
+		wantReason RejectReason
+	}{
+		{
+			name:       "allowed source and fresh",
+			remoteAddr: "103.10.128.7:41000",
+			body:       "trx_id=1&status_code=1&created_at=2024-05-01+11:00:00",
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:       "source outside allowlist",
+			remoteAddr: "192.0.2.1:41000",
+			body:       "trx_id=1&status_code=1&created_at=2024-05-01+11:00:00",
+			wantCode:   http.StatusForbidden,
+			wantReason: RejectSource,
+		},
+		{
+			name:       "source from trusted proxy header",
+			remoteAddr: "10.0.0.1:41000",
+			forwarded:  "192.0.2.1, 10.0.0.5",
+			body:       "trx_id=1&status_code=1&created_at=2024-05-01+11:00:00",
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:       "stale callback",
+			remoteAddr: "103.10.128.7:41000",
+			body:       "trx_id=1&status_code=1&created_at=2024-04-01+11:00:00&paid_at=2024-04-01+11:05:00",
+			wantCode:   http.StatusBadRequest,
+			wantReason: RejectStale,
+		},
+		{
+			name:       "settled days after payment",
+			remoteAddr: "103.10.128.7:41000",
+			body:       "trx_id=1&status_code=1&settlement_status=settled&created_at=2024-04-29+10:00:00&paid_at=2024-04-29+10:05:00",
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:       "settled beyond the settlement window",
+			remoteAddr: "103.10.128.7:41000",
+			body:       "trx_id=1&status_code=1&settlement_status=settled&created_at=2024-04-01+11:00:00&paid_at=2024-04-01+11:05:00",
+			wantCode:   http.StatusBadRequest,
+			wantReason: RejectStale,
+		},
+		{
+			name:       "expired without paid_at",
+			remoteAddr: "103.10.128.7:41000",
+			body:       "trx_id=1&status_code=-2&created_at=2024-04-30+09:00:00&expired_at=2024-05-01+09:00:00",
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:       "unpaid pending is not freshened by expired_at",
+			remoteAddr: "103.10.128.7:41000",
+			body:       "trx_id=1&status_code=0&created_at=2024-04-29+09:00:00&expired_at=2024-05-01+09:00:00",
+			wantCode:   http.StatusBadRequest,
+			wantReason: RejectStale,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rejected *CallbackRejection
+			h := NewCallbackHandler(nil)
+			h.AllowedNetworks = networks
+			h.TrustedProxyHeader = "X-Forwarded-For"
+			h.MaxAge = 24 * time.Hour
+			h.Now = func() time.Time { return now }
+			h.OnReject = func(r *http.Request, rejection *CallbackRejection) {
+				rejected = rejection
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/notify", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("ServeHTTP() code = %v, want %v", rec.Code, tt.wantCode)
+			}
+			if tt.wantReason != "" && (rejected == nil || rejected.Reason != tt.wantReason) {
+				t.Errorf("ServeHTTP() rejection = %v, want %v", rejected, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestCallbackHandler_Enrich(t *testing.T) {
+	var fetches int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		w.Write([]byte(`{"Status":200,"Data":{"TransactionId":96748,"SessionId":"abc-123","ReferenceId":"trx-123","Amount":100000,"Fee":4000,"Status":1,"TypeDesc":"VA"},"Message":"success"}`))
+	}))
+	defer srv.Close()
+
+	cl := &Client{}
+	cl.AssignCredential("api-key", "1179000899", EnvironmentType(srv.URL))
+
+	var events []*CallbackEvent
+	h := NewCallbackHandler(func(r *http.Request, event *CallbackEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	h.Client = cl
+	h.Verify = VerifyReject
+	h.Enrich = true
+	h.Cache = NewTransactionCache(time.Minute)
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/notify", strings.NewReader("trx_id=96748&status=berhasil&status_code=1"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("ServeHTTP() code = %v, want %v", rec.Code, http.StatusOK)
+		}
+	}
+
+	if fetches != 1 {
+		t.Errorf("CheckTransaction called %d times, want 1", fetches)
+	}
+	for _, event := range events {
+		if event.Transaction == nil || event.Transaction.Data.TypeDesc != "VA" {
+			t.Fatalf("event transaction = %+v, want enriched", event.Transaction)
+		}
+		if event.Callback.Amount != 100000 || event.Callback.Fee != 4000 || event.Callback.ReferenceID != "trx-123" {
+			t.Errorf("event callback = %+v, want fields merged from transaction", event.Callback)
+		}
+	}
+}
+
+func TestCallbackHandler_CacheMismatch(t *testing.T) {
+	var mu sync.Mutex
+	status, fetches := Pending, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		fetches++
+		fmt.Fprintf(w, `{"Status":200,"Data":{"TransactionId":96748,"ReferenceId":"trx-123","Amount":100000,"Status":%d},"Message":"success"}`, status)
+	}))
+	defer srv.Close()
+
+	cl := &Client{}
+	cl.AssignCredential("api-key", "1179000899", EnvironmentType(srv.URL))
+
+	h := NewCallbackHandler(nil)
+	h.Client = cl
+	h.Verify = VerifyReject
+	h.Cache = NewTransactionCache(time.Minute)
+
+	post := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/notify", strings.NewReader("trx_id=96748&status=berhasil&status_code=1"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// The callback arrives before CheckTransaction shows the transaction as paid.
+	if code := post(); code != http.StatusBadRequest {
+		t.Fatalf("early callback code = %v, want %v", code, http.StatusBadRequest)
+	}
+
+	mu.Lock()
+	status = Success
+	mu.Unlock()
+
+	for i := 0; i < 2; i++ {
+		if code := post(); code != http.StatusOK {
+			t.Fatalf("retried callback code = %v, want %v", code, http.StatusOK)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if fetches != 2 {
+		t.Errorf("CheckTransaction called %d times, want 2", fetches)
+	}
+}
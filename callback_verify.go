@@ -0,0 +1,109 @@
+package ipaymu_go_api
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type VerifyMode int8
+
+const (
+	// VerifyNone passes callbacks to the handler without checking them.
+	VerifyNone VerifyMode = 0
+	// VerifyReject rejects callbacks that do not match CheckTransaction.
+	VerifyReject VerifyMode = 1
+	// VerifyFlag passes mismatching callbacks to the handler with CallbackEvent.Mismatch set.
+	VerifyFlag VerifyMode = 2
+)
+
+type RejectReason string
+
+const (
+	RejectMalformed    RejectReason = "malformed"
+	RejectVerifyFailed RejectReason = "verify_failed"
+	RejectMismatch     RejectReason = "mismatch"
+	RejectSource       RejectReason = "source"
+	RejectStale        RejectReason = "stale"
+	RejectEnrichFailed RejectReason = "enrich_failed"
+)
+
+// CallbackRejection describes why a callback was not passed to the handler.
+type CallbackRejection struct {
+	Reason RejectReason
+	Err    error
+}
+
+func (e *CallbackRejection) Error() string {
+	return fmt.Sprintf("callback rejected (%s): %v", e.Reason, e.Err)
+}
+
+func (e *CallbackRejection) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode returns the HTTP status the handler replies with for the rejection.
+//
+// Rejections caused by a failure on our side use 503 Service Unavailable so that iPaymu retries them.
+func (e *CallbackRejection) StatusCode() int {
+	switch e.Reason {
+	case RejectVerifyFailed, RejectEnrichFailed:
+		return http.StatusServiceUnavailable
+	case RejectSource:
+		return http.StatusForbidden
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// verify checks the callback of event against CheckTransaction according to h.Verify.
+// It stores the fetched transaction on the event and returns a rejection when the callback must not reach Func.
+func (h *CallbackHandler) verify(event *CallbackEvent) *CallbackRejection {
+	if h.Verify == VerifyNone {
+		return nil
+	}
+	check, err := h.checkTransaction(event.Callback)
+	if err != nil {
+		return &CallbackRejection{Reason: RejectVerifyFailed, Err: err}
+	}
+	event.Transaction = &check
+
+	if err := CompareCallback(event.Callback, check); err != nil {
+		mismatch := &CallbackRejection{Reason: RejectMismatch, Err: err}
+		if h.Verify == VerifyReject {
+			return mismatch
+		}
+		event.Mismatch = mismatch
+		return nil
+	}
+
+	event.Verified = true
+	return nil
+}
+
+// CompareCallback compares a callback with the transaction returned by CheckTransaction.
+//
+// The status, amount, and reference ID must match; the amount and reference ID are only compared
+// when the callback carries them. Paid statuses count as the same status, since a callback
+// reported as "berhasil" is commonly still SuccessUnsettle in CheckTransaction.
+// It returns an error describing the first difference.
+func CompareCallback(cb RequestCallBack, check ResponseCheck) error {
+	if status := check.Data.Status; !sameStatus(cb.PaymentStatus(), status) {
+		return fmt.Errorf("status %s does not match transaction status %s", cb.PaymentStatus(), status)
+	}
+	if cb.Amount != 0 && cb.Amount != check.Data.Amount {
+		return fmt.Errorf("amount %s does not match transaction amount %s", cb.Amount, check.Data.Amount)
+	}
+	ref := check.Data.ReferenceId.String()
+	if cb.ReferenceID != "" && cb.ReferenceID != ref {
+		return fmt.Errorf("reference id %q does not match transaction reference id %q", cb.ReferenceID, ref)
+	}
+	return nil
+}
+
+// sameStatus reports whether a callback status agrees with the status of the transaction.
+func sameStatus(callback, transaction PaymentStatus) bool {
+	if callback.IsPaid() && transaction.IsPaid() {
+		return true
+	}
+	return callback == transaction
+}
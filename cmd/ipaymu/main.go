@@ -0,0 +1,83 @@
+// Command ipaymu contains development tools for the iPaymu Go API.
+//
+// Usage:
+//
+//	ipaymu simulate -url http://localhost:8080/notify-url -trx 96748 -ref trx-123 -status berhasil
+//	ipaymu simulate -url http://localhost:8080/notify-url -trx 96748 -ref trx-123 -sequence
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	ipaymu "github.com/ipaymu/ipaymu-go-api"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "simulate":
+		if err := simulate(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: ipaymu simulate [flags]")
+	fmt.Fprintln(os.Stderr, "run 'ipaymu simulate -h' for the flags")
+}
+
+// simulate posts a simulated callback, or a full pending, paid, settled sequence, to a local notify URL.
+func simulate(args []string) error {
+	fs := flag.NewFlagSet("simulate", flag.ExitOnError)
+	notifyURL := fs.String("url", "http://localhost:8080/notify-url", "notify URL to post the callback to")
+	trxID := fs.Int("trx", 0, "transaction ID of the callback")
+	referenceID := fs.String("ref", "", "reference ID of the callback")
+	status := fs.String("status", "berhasil", "payment status, as a code or a status text")
+	settled := fs.Bool("settled", false, "mark a successful callback as settled")
+	format := fs.String("format", string(ipaymu.FormCallback), "callback format, form or json")
+	sequence := fs.Bool("sequence", false, "post the pending, paid and settled callbacks in order")
+	interval := fs.Duration("interval", time.Second, "wait between the callbacks of a sequence")
+	fs.Parse(args)
+
+	if *trxID == 0 {
+		return fmt.Errorf("-trx is required")
+	}
+
+	ctx := context.Background()
+	if *sequence {
+		if err := ipaymu.SimulateSequence(ctx, *notifyURL, *trxID, *referenceID, ipaymu.CallbackFormat(*format), *interval); err != nil {
+			return err
+		}
+		fmt.Println("sequence delivered")
+		return nil
+	}
+
+	paymentStatus, err := ipaymu.ParsePaymentStatus(*status)
+	if err != nil {
+		return err
+	}
+	cb := ipaymu.NewSimulatedCallback(*trxID, *referenceID, paymentStatus)
+	if *settled {
+		cb.SettlementStatus = "settled"
+	}
+
+	code, err := ipaymu.SimulateCallback(ctx, *notifyURL, cb, ipaymu.CallbackFormat(*format))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("callback delivered, handler answered %d\n", code)
+	return nil
+}
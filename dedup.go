@@ -0,0 +1,145 @@
+package ipaymu_go_api
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// DedupStore records which callbacks have been handled, so that retried or repeated
+// callbacks reach the handler at most once per state transition.
+type DedupStore interface {
+	// Claim records key and reports whether it had not been claimed before.
+	// It must be atomic: of concurrent claims for the same key only one may succeed.
+	Claim(key string) (bool, error)
+	// Release removes the claim for key so that it can be claimed again.
+	Release(key string) error
+}
+
+// DedupKey returns the key a callback is deduplicated on: its transaction ID and status.
+// Settlement of a paid transaction counts as a separate transition.
+func DedupKey(cb RequestCallBack) string {
+	if cb.Settled() {
+		return fmt.Sprintf("%d:%d:settled", cb.TrxID, cb.PaymentStatus())
+	}
+	return fmt.Sprintf("%d:%d", cb.TrxID, cb.PaymentStatus())
+}
+
+// MemoryDedupStore is a DedupStore that keeps its keys in memory.
+// Claims are lost when the process exits.
+type MemoryDedupStore struct {
+	mu   sync.Mutex
+	keys map[string]struct{}
+}
+
+// NewMemoryDedupStore creates an empty MemoryDedupStore.
+func NewMemoryDedupStore() *MemoryDedupStore {
+	return &MemoryDedupStore{
+		keys: make(map[string]struct{}),
+	}
+}
+
+func (s *MemoryDedupStore) Claim(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.keys[key]; ok {
+		return false, nil
+	}
+	s.keys[key] = struct{}{}
+	return true, nil
+}
+
+func (s *MemoryDedupStore) Release(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.keys, key)
+	return nil
+}
+
+// FileDedupStore is a DedupStore that persists its keys in an append-only file,
+// so that claims survive restarts. Every claim is synced to disk before it is reported.
+type FileDedupStore struct {
+	mu   sync.Mutex
+	file *os.File
+	keys map[string]struct{}
+}
+
+// NewFileDedupStore opens the dedup file at path, creating it when it does not exist,
+// and loads the keys claimed in it.
+func NewFileDedupStore(path string) (*FileDedupStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]struct{})
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "+"):
+			keys[line[1:]] = struct{}{}
+		case strings.HasPrefix(line, "-"):
+			delete(keys, line[1:])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &FileDedupStore{
+		file: file,
+		keys: keys,
+	}, nil
+}
+
+func (s *FileDedupStore) Claim(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.keys[key]; ok {
+		return false, nil
+	}
+	if err := s.append("+" + key); err != nil {
+		return false, err
+	}
+	s.keys[key] = struct{}{}
+	return true, nil
+}
+
+func (s *FileDedupStore) Release(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.keys[key]; !ok {
+		return nil
+	}
+	if err := s.append("-" + key); err != nil {
+		return err
+	}
+	delete(s.keys, key)
+	return nil
+}
+
+// Close closes the underlying file.
+func (s *FileDedupStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+func (s *FileDedupStore) append(line string) error {
+	if strings.ContainsAny(line, "\r\n") {
+		return fmt.Errorf("invalid dedup key %q", line[1:])
+	}
+	if _, err := s.file.WriteString(line + "\n"); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
@@ -0,0 +1,137 @@
+package ipaymu_go_api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFileDedupStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "callbacks.dedup")
+
+	store, err := NewFileDedupStore(path)
+	if err != nil {
+		t.Fatalf("NewFileDedupStore() error = %v", err)
+	}
+	for _, key := range []string{"1:1", "2:1", "3:0"} {
+		if ok, err := store.Claim(key); !ok || err != nil {
+			t.Fatalf("Claim(%q) = %v, %v, want true", key, ok, err)
+		}
+	}
+	if err := store.Release("2:1"); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	store.Close()
+
+	store, err = NewFileDedupStore(path)
+	if err != nil {
+		t.Fatalf("NewFileDedupStore() reopen error = %v", err)
+	}
+	defer store.Close()
+
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{key: "1:1", want: false},
+		{key: "2:1", want: true},
+		{key: "3:0", want: false},
+		{key: "3:1", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := store.Claim(tt.key)
+			if err != nil || got != tt.want {
+				t.Errorf("Claim() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCallbackHandler_Dedup(t *testing.T) {
+	var calls int32
+	h := NewCallbackHandler(func(r *http.Request, event *CallbackEvent) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+	h.Dedup = NewMemoryDedupStore()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "/notify", strings.NewReader("trx_id=96748&status=berhasil&status_code=1"))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Errorf("ServeHTTP() code = %v, want %v", rec.Code, http.StatusOK)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+}
+
+func TestCallbackHandler_DedupSettled(t *testing.T) {
+	tests := []struct {
+		name      string
+		bodies    []string
+		wantCalls []string
+	}{
+		{
+			name: "paid then settled",
+			bodies: []string{
+				"trx_id=96748&status=berhasil&status_code=1&settlement_status=unsettled",
+				"trx_id=96748&status=berhasil&status_code=1&settlement_status=settled",
+				"trx_id=96748&status=berhasil&status_code=1&settlement_status=settled",
+			},
+			wantCalls: []string{"success", "settled"},
+		},
+		{
+			name: "settled before paid",
+			bodies: []string{
+				"trx_id=96748&status=berhasil&status_code=1&settlement_status=settled",
+				"trx_id=96748&status=berhasil&status_code=1&settlement_status=unsettled",
+			},
+			wantCalls: []string{"success", "settled"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			d := NewCallbackDispatcher()
+			d.OnSuccess(func(r *http.Request, event *CallbackEvent) error {
+				calls = append(calls, "success")
+				return nil
+			})
+			d.OnSettled(func(r *http.Request, event *CallbackEvent) error {
+				calls = append(calls, "settled")
+				return nil
+			})
+			h := NewCallbackHandler(d.Handle)
+			h.Dedup = NewMemoryDedupStore()
+
+			for _, body := range tt.bodies {
+				req := httptest.NewRequest(http.MethodPost, "/notify", strings.NewReader(body))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, req)
+				if rec.Code != http.StatusOK {
+					t.Fatalf("ServeHTTP(%s) code = %v, want %v", body, rec.Code, http.StatusOK)
+				}
+			}
+			if strings.Join(calls, ",") != strings.Join(tt.wantCalls, ",") {
+				t.Errorf("calls = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
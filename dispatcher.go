@@ -0,0 +1,165 @@
+package ipaymu_go_api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+// CallbackMiddleware wraps a CallbackFunc, for example to log or recover from panics.
+type CallbackMiddleware func(next CallbackFunc) CallbackFunc
+
+// ErrSkipCallback is returned by a fallback handler to pass the callback on to the next fallback.
+var ErrSkipCallback = errors.New("callback skipped")
+
+// CallbackDispatcher routes callbacks to handlers registered per PaymentStatus.
+//
+// Its Handle method is a CallbackFunc, so it can be used with NewCallbackHandler:
+//
+//	dispatcher := ipaymu.NewCallbackDispatcher()
+//	dispatcher.Use(ipaymu.RecoverCallback(), ipaymu.LogCallback(nil))
+//	dispatcher.OnSuccess(markPaid)
+//	dispatcher.OnExpired(cancelOrder)
+//	http.Handle("/notify-url", ipaymu.NewCallbackHandler(dispatcher.Handle))
+type CallbackDispatcher struct {
+	handlers    map[PaymentStatus]CallbackFunc
+	settled     CallbackFunc
+	fallbacks   []CallbackFunc
+	middlewares []CallbackMiddleware
+}
+
+// NewCallbackDispatcher creates a CallbackDispatcher without any handlers.
+func NewCallbackDispatcher() *CallbackDispatcher {
+	return &CallbackDispatcher{
+		handlers: make(map[PaymentStatus]CallbackFunc),
+	}
+}
+
+// On registers fn for callbacks with the given statuses, replacing any handler registered before.
+func (d *CallbackDispatcher) On(fn CallbackFunc, statuses ...PaymentStatus) *CallbackDispatcher {
+	for _, status := range statuses {
+		d.handlers[status] = fn
+	}
+	return d
+}
+
+// OnSuccess registers fn for paid callbacks, Success and SuccessUnsettle.
+func (d *CallbackDispatcher) OnSuccess(fn CallbackFunc) *CallbackDispatcher {
+	return d.On(fn, Success, SuccessUnsettle)
+}
+
+// OnPending registers fn for Pending callbacks.
+func (d *CallbackDispatcher) OnPending(fn CallbackFunc) *CallbackDispatcher {
+	return d.On(fn, Pending)
+}
+
+// OnExpired registers fn for Expired callbacks.
+func (d *CallbackDispatcher) OnExpired(fn CallbackFunc) *CallbackDispatcher {
+	return d.On(fn, Expired)
+}
+
+// OnFailed registers fn for Failed and Error callbacks.
+func (d *CallbackDispatcher) OnFailed(fn CallbackFunc) *CallbackDispatcher {
+	return d.On(fn, Failed, Error)
+}
+
+// OnRefund registers fn for Refund callbacks.
+func (d *CallbackDispatcher) OnRefund(fn CallbackFunc) *CallbackDispatcher {
+	return d.On(fn, Refund)
+}
+
+// OnSettled registers fn for Success callbacks whose settlement status is "settled".
+//
+// Settled callbacks go to fn instead of the OnSuccess handler, so a payment that is first
+// reported paid and later settled reaches each handler once. Only when CallbackEvent.FirstPaid
+// is set, because no paid callback was handled before, does the OnSuccess handler run first.
+func (d *CallbackDispatcher) OnSettled(fn CallbackFunc) *CallbackDispatcher {
+	d.settled = fn
+	return d
+}
+
+// Fallback appends fn to the handlers run, in registration order, for callbacks without a status handler.
+// A fallback returns ErrSkipCallback to pass the callback on to the next one.
+func (d *CallbackDispatcher) Fallback(fn CallbackFunc) *CallbackDispatcher {
+	d.fallbacks = append(d.fallbacks, fn)
+	return d
+}
+
+// Use appends middlewares around the dispatch. The first middleware is the outermost.
+func (d *CallbackDispatcher) Use(middlewares ...CallbackMiddleware) *CallbackDispatcher {
+	d.middlewares = append(d.middlewares, middlewares...)
+	return d
+}
+
+// Handle dispatches the callback to the registered handlers. It implements CallbackFunc.
+//
+// Callbacks that no handler or fallback takes are acknowledged without error.
+func (d *CallbackDispatcher) Handle(r *http.Request, event *CallbackEvent) error {
+	fn := d.dispatch
+	for i := len(d.middlewares) - 1; i >= 0; i-- {
+		fn = d.middlewares[i](fn)
+	}
+	return fn(r, event)
+}
+
+func (d *CallbackDispatcher) dispatch(r *http.Request, event *CallbackEvent) error {
+	if event.Callback.Settled() {
+		if fn, ok := d.handlers[event.Status]; ok && event.FirstPaid {
+			if err := fn(r, event); err != nil {
+				return err
+			}
+		}
+		if d.settled != nil {
+			return d.settled(r, event)
+		}
+		return nil
+	}
+
+	if fn, ok := d.handlers[event.Status]; ok {
+		return fn(r, event)
+	}
+
+	for _, fallback := range d.fallbacks {
+		if err := fallback(r, event); !errors.Is(err, ErrSkipCallback) {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecoverCallback returns a middleware that turns a panic in the handler into an error,
+// so that the callback is answered with 500 Internal Server Error and retried by iPaymu.
+func RecoverCallback() CallbackMiddleware {
+	return func(next CallbackFunc) CallbackFunc {
+		return func(r *http.Request, event *CallbackEvent) (err error) {
+			defer func() {
+				if p := recover(); p != nil {
+					log.Printf("panic handling callback for transaction %d: %v\n%s", event.Callback.TrxID, p, debug.Stack())
+					err = fmt.Errorf("panic handling callback: %v", p)
+				}
+			}()
+			return next(r, event)
+		}
+	}
+}
+
+// LogCallback returns a middleware that logs every callback and the outcome of its handler.
+// When logger is nil the standard logger is used.
+func LogCallback(logger *log.Logger) CallbackMiddleware {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return func(next CallbackFunc) CallbackFunc {
+		return func(r *http.Request, event *CallbackEvent) error {
+			err := next(r, event)
+			if err != nil {
+				logger.Printf("callback trx_id=%d status=%s reference_id=%q failed: %v", event.Callback.TrxID, event.Status, event.Callback.ReferenceID, err)
+			} else {
+				logger.Printf("callback trx_id=%d status=%s reference_id=%q handled", event.Callback.TrxID, event.Status, event.Callback.ReferenceID)
+			}
+			return err
+		}
+	}
+}
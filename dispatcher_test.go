@@ -0,0 +1,80 @@
+package ipaymu_go_api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestCallbackDispatcher_Handle(t *testing.T) {
+	var calls []string
+	record := func(name string) CallbackFunc {
+		return func(r *http.Request, event *CallbackEvent) error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+
+	d := NewCallbackDispatcher()
+	d.Use(RecoverCallback())
+	d.OnSuccess(record("success"))
+	d.OnSettled(record("settled"))
+	d.OnExpired(func(r *http.Request, event *CallbackEvent) error {
+		panic("boom")
+	})
+	d.Fallback(func(r *http.Request, event *CallbackEvent) error {
+		calls = append(calls, "skip")
+		return ErrSkipCallback
+	})
+	d.Fallback(record("fallback"))
+
+	tests := []struct {
+		name      string
+		callback  RequestCallBack
+		firstPaid bool
+		wantCalls []string
+		wantErr   bool
+	}{
+		{
+			name:      "success unsettled",
+			callback:  RequestCallBack{StatusCode: Success, SettlementStatus: "unsettled"},
+			wantCalls: []string{"success"},
+		},
+		{
+			name:      "success settled",
+			callback:  RequestCallBack{StatusCode: Success, SettlementStatus: "settled"},
+			wantCalls: []string{"settled"},
+		},
+		{
+			name:      "settled before any paid callback",
+			callback:  RequestCallBack{StatusCode: Success, SettlementStatus: "settled"},
+			firstPaid: true,
+			wantCalls: []string{"success", "settled"},
+		},
+		{
+			name:      "panic is recovered",
+			callback:  RequestCallBack{StatusCode: Expired},
+			wantCalls: nil,
+			wantErr:   true,
+		},
+		{
+			name:      "unknown status goes to fallbacks",
+			callback:  RequestCallBack{StatusCode: Escrow},
+			wantCalls: []string{"skip", "fallback"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			event := &CallbackEvent{Callback: tt.callback, Status: tt.callback.PaymentStatus(), FirstPaid: tt.firstPaid}
+			err := d.Handle(httptest.NewRequest(http.MethodPost, "/notify", nil), event)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Handle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Errorf("Handle() calls = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
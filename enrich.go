@@ -0,0 +1,112 @@
+package ipaymu_go_api
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TransactionCache keeps CheckTransaction results for callbacks, keyed on transaction ID and
+// status, so that repeated callbacks for the same state do not fetch the transaction again.
+type TransactionCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cachedTransaction
+}
+
+type cachedTransaction struct {
+	check   ResponseCheck
+	expires time.Time
+}
+
+// NewTransactionCache creates a TransactionCache whose entries expire after ttl.
+func NewTransactionCache(ttl time.Duration) *TransactionCache {
+	return &TransactionCache{
+		ttl:     ttl,
+		entries: make(map[string]cachedTransaction),
+	}
+}
+
+// Get returns the cached transaction for key, if any.
+func (c *TransactionCache) Get(key string) (ResponseCheck, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return ResponseCheck{}, false
+	}
+	return entry.check, true
+}
+
+// Set stores the transaction for key, removing expired entries on the way.
+func (c *TransactionCache) Set(key string, check ResponseCheck) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cachedTransaction{check: check, expires: now.Add(c.ttl)}
+}
+
+// checkTransaction returns the transaction of the callback, from h.Cache when possible.
+//
+// Only transactions that match the callback are cached. A callback that is forged, or that arrives
+// before CheckTransaction reflects it, must not leave a result behind that rejects the genuine one.
+func (h *CallbackHandler) checkTransaction(cb RequestCallBack) (ResponseCheck, error) {
+	if h.Client == nil {
+		return ResponseCheck{}, fmt.Errorf("no client to check the transaction with")
+	}
+
+	key := DedupKey(cb)
+	if h.Cache != nil {
+		if check, ok := h.Cache.Get(key); ok {
+			return check, nil
+		}
+	}
+
+	check, err := h.Client.CheckTransaction(cb.TrxID)
+	if err != nil {
+		return check, err
+	}
+	if h.Cache != nil && CompareCallback(cb, check) == nil {
+		h.Cache.Set(key, check)
+	}
+	return check, nil
+}
+
+// enrich sets the transaction details on the event when h.Enrich is set, and fills the
+// callback fields iPaymu left empty from them.
+func (h *CallbackHandler) enrich(event *CallbackEvent) *CallbackRejection {
+	if !h.Enrich {
+		return nil
+	}
+	if event.Transaction == nil {
+		check, err := h.checkTransaction(event.Callback)
+		if err != nil {
+			return &CallbackRejection{Reason: RejectEnrichFailed, Err: err}
+		}
+		event.Transaction = &check
+	}
+
+	data := event.Transaction.Data
+	cb := &event.Callback
+	if cb.SID == "" {
+		cb.SID = data.SessionId.String()
+	}
+	if cb.ReferenceID == "" {
+		cb.ReferenceID = data.ReferenceId.String()
+	}
+	if cb.Amount == 0 {
+		cb.Amount = data.Amount
+	}
+	if cb.Fee == 0 {
+		cb.Fee = data.Fee
+	}
+	return nil
+}
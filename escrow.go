@@ -0,0 +1,86 @@
+package ipaymu_go_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// ReleaseEscrow releases the funds held in escrow for a transaction to the receiver.
+//
+// transactionID: The identifier of the escrow transaction.
+//
+// Return values:
+// - res: A ResponseEscrow struct containing the updated transaction status.
+// - err: An error if any occurred during the API call or response parsing.
+//
+// If the API call fails or the status code is not 200, the function returns an error with the corresponding message.
+func (c *Client) ReleaseEscrow(transactionID int) (res ResponseEscrow, err error) {
+	uri, _ := url.Parse(fmt.Sprintf("%s/api/v2/escrow/release", c.EnvApi))
+	jsonBody, _ := json.Marshal(map[string]int{"transactionId": transactionID})
+	signature := c.signature(jsonBody)
+	api, err := c.CallApi(uri, signature, jsonBody)
+	if err != nil {
+		return
+	}
+
+	err = c.decodeResponse(uri, api, &res)
+	if err != nil {
+		return
+	}
+
+	if res.Status != 200 {
+		return res, fmt.Errorf("%s", res.Message)
+	}
+
+	return
+}
+
+// RefundEscrow refunds the funds held in escrow for a transaction back to the buyer.
+//
+// transactionID: The identifier of the escrow transaction.
+//
+// Return values:
+// - res: A ResponseEscrow struct containing the updated transaction status.
+// - err: An error if any occurred during the API call or response parsing.
+//
+// If the API call fails or the status code is not 200, the function returns an error with the corresponding message.
+func (c *Client) RefundEscrow(transactionID int) (res ResponseEscrow, err error) {
+	uri, _ := url.Parse(fmt.Sprintf("%s/api/v2/escrow/refund", c.EnvApi))
+	jsonBody, _ := json.Marshal(map[string]int{"transactionId": transactionID})
+	signature := c.signature(jsonBody)
+	api, err := c.CallApi(uri, signature, jsonBody)
+	if err != nil {
+		return
+	}
+
+	err = c.decodeResponse(uri, api, &res)
+	if err != nil {
+		return
+	}
+
+	if res.Status != 200 {
+		return res, fmt.Errorf("%s", res.Message)
+	}
+
+	return
+}
+
+// EscrowTransactions lists the transactions currently held in escrow.
+//
+// The Status filter of the request is set to Escrow; other filters such as dates and limit are kept.
+// All pages are read, starting at the page of the request. Only transactions flagged IsEscrow
+// in the response are returned.
+func (c *Client) EscrowTransactions(request RequestTransactionHistory) (trx []Transaction, err error) {
+	status := Escrow
+	request.Status = &status
+
+	err = NewHistoryPager(c, request).Each(context.Background(), func(t Transaction) error {
+		if t.IsEscrow {
+			trx = append(trx, t)
+		}
+		return nil
+	})
+	return
+}
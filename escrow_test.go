@@ -0,0 +1,112 @@
+package ipaymu_go_api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_ReleaseRefundEscrow(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			TransactionID int `json:"transactionId"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+
+		switch r.URL.Path {
+		case "/api/v2/escrow/release":
+			fmt.Fprintf(w, `{"Status":200,"Data":{"TransactionId":%d,"Status":1,"StatusDesc":"Berhasil"},"Message":"success"}`, request.TransactionID)
+		case "/api/v2/escrow/refund":
+			fmt.Fprintf(w, `{"Status":200,"Data":{"TransactionId":%d,"Status":3,"StatusDesc":"Refund"},"Message":"success"}`, request.TransactionID)
+		default:
+			w.Write([]byte(`{"Status":404,"Message":"not found"}`))
+		}
+	}))
+	defer srv.Close()
+
+	cl := &Client{}
+	cl.AssignCredential("api-key", "1179000899", EnvironmentType(srv.URL))
+
+	tests := []struct {
+		name       string
+		call       func(int) (ResponseEscrow, error)
+		wantStatus PaymentStatus
+	}{
+		{name: "release", call: cl.ReleaseEscrow, wantStatus: Success},
+		{name: "refund", call: cl.RefundEscrow, wantStatus: Refund},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tt.call(96748)
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if res.Data.TransactionId != 96748 || res.Data.Status != tt.wantStatus {
+				t.Errorf("Data = %+v, want transaction 96748 with status %s", res.Data, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestClient_EscrowTransactions(t *testing.T) {
+	const totalPages = 3
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request RequestTransactionHistory
+		json.NewDecoder(r.Body).Decode(&request)
+		if request.Status == nil || *request.Status != Escrow || request.Page == nil {
+			t.Errorf("history request = %+v, want escrow status and page", request)
+			return
+		}
+		// Every page holds two escrow transactions and one that was already released.
+		page := *request.Page
+		fmt.Fprintf(w, `{"Status":200,"Success":true,"Message":"success","Data":{"Transaction":[
+			{"TransactionId":%d,"IsEscrow":true},{"TransactionId":%d,"IsEscrow":true},{"TransactionId":%d,"IsEscrow":false}
+		],"Pagination":{"total":%d,"count":3,"per_page":3,"current_page":%d,"total_pages":%d}}}`,
+			page*10+1, page*10+2, page*10+3, totalPages*3, page, totalPages)
+	}))
+	defer srv.Close()
+
+	cl := &Client{}
+	cl.AssignCredential("api-key", "1179000899", EnvironmentType(srv.URL))
+
+	trx, err := cl.EscrowTransactions(*NewRequestTransactionHistory())
+	if err != nil {
+		t.Fatalf("EscrowTransactions() error = %v", err)
+	}
+	var got []int
+	for _, tr := range trx {
+		got = append(got, tr.TransactionId.Int())
+	}
+	want := []int{11, 12, 21, 22, 31, 32}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("EscrowTransactions() = %v, want %v", got, want)
+	}
+}
+
+func TestEnableEscrow(t *testing.T) {
+	direct := NewRequestDirectVA(BNI)
+	direct.EnableEscrow()
+	redirect := NewRequestRedirect()
+	redirect.EnableEscrow()
+
+	for name, request := range map[string]interface{}{"direct": direct, "redirect": redirect} {
+		body, err := json.Marshal(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got map[string]interface{}
+		json.Unmarshal(body, &got)
+		if got["escrow"] != true {
+			t.Errorf("%s request escrow = %v, want true", name, got["escrow"])
+		}
+	}
+
+	body, _ := json.Marshal(NewRequestRedirect())
+	var got map[string]interface{}
+	json.Unmarshal(body, &got)
+	if _, ok := got["escrow"]; ok {
+		t.Errorf("request without escrow sent escrow = %v", got["escrow"])
+	}
+}
@@ -0,0 +1,91 @@
+package ipaymu_go_api
+
+import (
+	"context"
+)
+
+// HistoryPager walks every page of HistoryTransaction for a request.
+//
+// Paging starts at Request.Page, or at the first page when it is not set, and stops after
+// the last page reported by the pagination data. When Prefetch is set, the next page is
+// fetched while the transactions of the current page are handled.
+type HistoryPager struct {
+	Client   ClientApi
+	Request  RequestTransactionHistory
+	Prefetch bool
+}
+
+// NewHistoryPager creates a HistoryPager for the transactions matching request.
+func NewHistoryPager(client ClientApi, request RequestTransactionHistory) *HistoryPager {
+	return &HistoryPager{
+		Client:  client,
+		Request: request,
+	}
+}
+
+type historyPage struct {
+	res ResponseTransaction
+	err error
+}
+
+// Each calls fn for every transaction, page by page. It stops at the first error returned by
+// fn or by HistoryTransaction, and when ctx is done.
+func (p *HistoryPager) Each(ctx context.Context, fn func(Transaction) error) error {
+	page := 1
+	if p.Request.Page != nil && *p.Request.Page > 0 {
+		page = *p.Request.Page
+	}
+
+	next := p.fetch(page)
+	for {
+		var current historyPage
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case current = <-next:
+		}
+		if current.err != nil {
+			return current.err
+		}
+
+		data := current.res.Data
+		more := len(data.Transaction) > 0
+		if data.Pagination.TotalPages > 0 {
+			more = page < data.Pagination.TotalPages.Int()
+		}
+		if more && p.Prefetch {
+			next = p.fetch(page + 1)
+		}
+
+		for _, trx := range data.Transaction {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := fn(trx); err != nil {
+				return err
+			}
+		}
+
+		if !more {
+			return nil
+		}
+		page++
+		if !p.Prefetch {
+			next = p.fetch(page)
+		}
+	}
+}
+
+// fetch requests a page in the background. The channel is buffered, so the request
+// finishes even when nobody receives the result.
+func (p *HistoryPager) fetch(page int) <-chan historyPage {
+	request := p.Request
+	request.Page = &page
+
+	ch := make(chan historyPage, 1)
+	go func() {
+		res, err := p.Client.HistoryTransaction(request)
+		ch <- historyPage{res: res, err: err}
+	}()
+	return ch
+}
@@ -0,0 +1,27 @@
+//go:build go1.23
+
+package ipaymu_go_api
+
+import (
+	"context"
+	"errors"
+	"iter"
+)
+
+var errStopIteration = errors.New("iteration stopped")
+
+// All returns an iterator over every transaction, walking all pages like Each.
+// An error ends the iteration and is yielded with an empty Transaction.
+func (p *HistoryPager) All(ctx context.Context) iter.Seq2[Transaction, error] {
+	return func(yield func(Transaction, error) bool) {
+		err := p.Each(ctx, func(trx Transaction) error {
+			if !yield(trx, nil) {
+				return errStopIteration
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errStopIteration) {
+			yield(Transaction{}, err)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+//go:build go1.23
+
+package ipaymu_go_api
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHistoryPager_All(t *testing.T) {
+	srv := newHistoryServer(t, 3)
+	defer srv.Close()
+
+	cl := &Client{}
+	cl.AssignCredential("api-key", "1179000899", EnvironmentType(srv.URL))
+
+	var got []int
+	for trx, err := range NewHistoryPager(cl, *NewRequestTransactionHistory()).All(context.Background()) {
+		if err != nil {
+			t.Fatalf("All() error = %v", err)
+		}
+		got = append(got, trx.TransactionId.Int())
+		if len(got) == 2 {
+			break
+		}
+	}
+	if len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("All() = %v, want [1 2]", got)
+	}
+}
@@ -0,0 +1,157 @@
+package ipaymu_go_api
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxHistoryLimit is the largest page size accepted by the history endpoint.
+const MaxHistoryLimit = 20
+
+// historyDateLayout is the Y-m-d format of the startdate and enddate filters.
+const historyDateLayout = "2006-01-02"
+
+// ErrInvalidHistoryQuery is returned when a RequestTransactionHistory would be rejected by iPaymu.
+var ErrInvalidHistoryQuery = errors.New("invalid history query")
+
+// Validate checks the request before it is sent: the limit must be between 1 and MaxHistoryLimit,
+// the page must be positive, and the start and end dates must be Y-m-d dates with start not after end.
+// Errors wrap ErrInvalidHistoryQuery.
+func (r RequestTransactionHistory) Validate() error {
+	if r.Limit != nil && (*r.Limit < 1 || *r.Limit > MaxHistoryLimit) {
+		return fmt.Errorf("%w: limit %d is not between 1 and %d", ErrInvalidHistoryQuery, *r.Limit, MaxHistoryLimit)
+	}
+	if r.Page != nil && *r.Page < 1 {
+		return fmt.Errorf("%w: page %d is not positive", ErrInvalidHistoryQuery, *r.Page)
+	}
+
+	var start, end time.Time
+	var err error
+	if r.StartDate != nil {
+		if start, err = time.Parse(historyDateLayout, *r.StartDate); err != nil {
+			return fmt.Errorf("%w: start date %q is not in Y-m-d format", ErrInvalidHistoryQuery, *r.StartDate)
+		}
+	}
+	if r.EndDate != nil {
+		if end, err = time.Parse(historyDateLayout, *r.EndDate); err != nil {
+			return fmt.Errorf("%w: end date %q is not in Y-m-d format", ErrInvalidHistoryQuery, *r.EndDate)
+		}
+	}
+	if r.StartDate != nil && r.EndDate != nil && start.After(end) {
+		return fmt.Errorf("%w: start date %s is after end date %s", ErrInvalidHistoryQuery, *r.StartDate, *r.EndDate)
+	}
+	return nil
+}
+
+// HistoryQuery builds a RequestTransactionHistory without taking the address of each filter value.
+//
+//	request, err := ipaymu.NewHistoryQuery().
+//		Status(ipaymu.Success).
+//		Between(ipaymu.PaidAt, from, to).
+//		OrderBy(ipaymu.Paid, ipaymu.DESC).
+//		Limit(20).
+//		Build()
+type HistoryQuery struct {
+	request RequestTransactionHistory
+	ids     []int
+}
+
+// NewHistoryQuery creates a HistoryQuery without any filters.
+func NewHistoryQuery() *HistoryQuery {
+	return &HistoryQuery{}
+}
+
+// ID filters on a single transaction ID.
+func (q *HistoryQuery) ID(id int) *HistoryQuery {
+	q.request.ID = &id
+	return q
+}
+
+// Status filters on the payment status.
+func (q *HistoryQuery) Status(status PaymentStatus) *HistoryQuery {
+	q.request.Status = &status
+	return q
+}
+
+// Between filters on transactions whose field date falls from start to end, both inclusive.
+// The dates are taken in Asia/Jakarta, the time zone iPaymu uses.
+func (q *HistoryQuery) Between(field FilterDate, start, end time.Time) *HistoryQuery {
+	startDate := start.In(jakarta).Format(historyDateLayout)
+	endDate := end.In(jakarta).Format(historyDateLayout)
+	q.request.Date = &field
+	q.request.StartDate = &startDate
+	q.request.EndDate = &endDate
+	return q
+}
+
+// Page sets the page to fetch, starting at 1.
+func (q *HistoryQuery) Page(page int) *HistoryQuery {
+	q.request.Page = &page
+	return q
+}
+
+// OrderBy sorts the transactions by field in the given order.
+func (q *HistoryQuery) OrderBy(field FilterOrderBy, order FilterOrder) *HistoryQuery {
+	q.request.OrderBy = &field
+	q.request.Order = &order
+	return q
+}
+
+// Limit sets the page size, at most MaxHistoryLimit.
+func (q *HistoryQuery) Limit(limit int) *HistoryQuery {
+	// Values that do not fit the int8 field are clamped so that Build still rejects them.
+	l := int8(MaxHistoryLimit + 1)
+	if limit < 1 {
+		l = 0
+	} else if limit <= MaxHistoryLimit {
+		l = int8(limit)
+	}
+	q.request.Limit = &l
+	return q
+}
+
+// Lang sets the language of the status descriptions.
+func (q *HistoryQuery) Lang(lang FilterLanguage) *HistoryQuery {
+	q.request.Lang = &lang
+	return q
+}
+
+// Transactions filters on the given transaction IDs, sent as the comma separated BulkId filter.
+// It adds to the IDs of earlier calls.
+func (q *HistoryQuery) Transactions(ids ...int) *HistoryQuery {
+	q.ids = append(q.ids, ids...)
+	return q
+}
+
+// Account filters on the transactions of a member account.
+func (q *HistoryQuery) Account(account string) *HistoryQuery {
+	q.request.Account = &account
+	return q
+}
+
+// LockStatus filters on whether the funds of the transactions are locked.
+func (q *HistoryQuery) LockStatus(status FilterLockStatus) *HistoryQuery {
+	q.request.LockStatus = &status
+	return q
+}
+
+// Build returns the request, or an error wrapping ErrInvalidHistoryQuery when it is not valid.
+func (q *HistoryQuery) Build() (RequestTransactionHistory, error) {
+	request := q.request
+	if len(q.ids) > 0 {
+		list := make([]string, len(q.ids))
+		for i, id := range q.ids {
+			list[i] = strconv.Itoa(id)
+		}
+		bulkID := strings.Join(list, ",")
+		request.BulkId = &bulkID
+	}
+
+	if err := request.Validate(); err != nil {
+		return request, err
+	}
+	return request, nil
+}
@@ -0,0 +1,112 @@
+package ipaymu_go_api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHistoryQuery_Build(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, jakarta)
+	// 2024-05-31 20:00 UTC is already June 1 in Jakarta.
+	to := time.Date(2024, 5, 31, 20, 0, 0, 0, time.UTC)
+
+	request, err := NewHistoryQuery().
+		Status(Success).
+		Between(PaidAt, from, to).
+		OrderBy(Paid, DESC).
+		Limit(20).
+		Lang(EN).
+		Transactions(96748, 96749).
+		Transactions(96750).
+		LockStatus(Unlocked).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"status":     float64(Success),
+		"date":       "indate",
+		"startdate":  "2024-05-01",
+		"enddate":    "2024-06-01",
+		"orderBy":    "indate",
+		"order":      "DESC",
+		"limit":      float64(20),
+		"lang":       "en",
+		"bulkId":     "96748,96749,96750",
+		"lockStatus": float64(0),
+		"id":         nil,
+		"page":       nil,
+		"account":    nil,
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("Build() %s = %v, want %v", key, got[key], value)
+		}
+	}
+}
+
+func TestHistoryQuery_Validate(t *testing.T) {
+	day := time.Date(2024, 5, 1, 12, 0, 0, 0, jakarta)
+	tests := []struct {
+		name    string
+		query   *HistoryQuery
+		wantErr bool
+	}{
+		{name: "empty", query: NewHistoryQuery(), wantErr: false},
+		{name: "max limit", query: NewHistoryQuery().Limit(MaxHistoryLimit), wantErr: false},
+		{name: "limit over max", query: NewHistoryQuery().Limit(21), wantErr: true},
+		{name: "limit over int8", query: NewHistoryQuery().Limit(1000), wantErr: true},
+		{name: "zero limit", query: NewHistoryQuery().Limit(0), wantErr: true},
+		{name: "zero page", query: NewHistoryQuery().Page(0), wantErr: true},
+		{name: "same day", query: NewHistoryQuery().Between(CreatedAt, day, day), wantErr: false},
+		{name: "start after end", query: NewHistoryQuery().Between(CreatedAt, day.AddDate(0, 0, 1), day), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.query.Build()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidHistoryQuery) {
+				t.Errorf("Build() error = %v, want wrapped ErrInvalidHistoryQuery", err)
+			}
+		})
+	}
+}
+
+func TestClient_HistoryTransaction_Invalid(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.Write([]byte(`{"Status":200,"Success":true,"Message":"success","Data":{"Transaction":[]}}`))
+	}))
+	defer srv.Close()
+
+	cl := &Client{}
+	cl.AssignCredential("api-key", "1179000899", EnvironmentType(srv.URL))
+
+	start, end := "2024-06-01", "2024-05-01"
+	request := NewRequestTransactionHistory()
+	request.StartDate = &start
+	request.EndDate = &end
+
+	if _, err := cl.HistoryTransaction(*request); !errors.Is(err, ErrInvalidHistoryQuery) {
+		t.Errorf("HistoryTransaction() error = %v, want ErrInvalidHistoryQuery", err)
+	}
+	if called {
+		t.Error("HistoryTransaction() sent an invalid request")
+	}
+}
@@ -0,0 +1,88 @@
+package ipaymu_go_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newHistoryServer serves totalPages pages of history with one transaction each,
+// whose TransactionId is the page number.
+func newHistoryServer(t *testing.T, totalPages int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request RequestTransactionHistory
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Page == nil {
+			t.Errorf("history request page missing: %v", err)
+			return
+		}
+		fmt.Fprintf(w, `{"Status":200,"Success":true,"Message":"success","Data":{"Transaction":[{"TransactionId":%d}],"Pagination":{"total":%d,"count":1,"per_page":1,"current_page":%d,"total_pages":%d}}}`,
+			*request.Page, totalPages, *request.Page, totalPages)
+	}))
+}
+
+func TestHistoryPager_Each(t *testing.T) {
+	srv := newHistoryServer(t, 130)
+	defer srv.Close()
+
+	cl := &Client{}
+	cl.AssignCredential("api-key", "1179000899", EnvironmentType(srv.URL))
+
+	tests := []struct {
+		name     string
+		prefetch bool
+		stopAt   int
+		want     int
+	}{
+		{
+			name: "all pages beyond 127",
+			want: 130,
+		},
+		{
+			name:     "all pages with prefetch",
+			prefetch: true,
+			want:     130,
+		},
+		{
+			name:     "stopped by cancellation",
+			prefetch: true,
+			stopAt:   5,
+			want:     5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			pager := NewHistoryPager(cl, *NewRequestTransactionHistory())
+			pager.Prefetch = tt.prefetch
+
+			var got []int
+			err := pager.Each(ctx, func(trx Transaction) error {
+				got = append(got, trx.TransactionId.Int())
+				if len(got) == tt.stopAt {
+					cancel()
+				}
+				return nil
+			})
+			if tt.stopAt > 0 {
+				if err != context.Canceled {
+					t.Errorf("Each() error = %v, want %v", err, context.Canceled)
+				}
+			} else if err != nil {
+				t.Fatalf("Each() error = %v", err)
+			}
+			if len(got) != tt.want {
+				t.Fatalf("Each() visited %d transactions, want %d", len(got), tt.want)
+			}
+			for i, id := range got {
+				if id != i+1 {
+					t.Fatalf("Each() transaction %d has id %d, want pages in order", i, id)
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package ipaymu_go_api
 
 import (
+	"context"
 	"io"
 	"io/ioutil"
 	"log"
@@ -20,6 +21,21 @@ type ClientApi interface {
 	DirectPaymentCOD(request RequestDirectCOD) (res Response, err error)
 	RedirectPayment(request RequestRedirect) (res Response, err error)
 	GetBalance() (res ResponseBalance, err error)
+	ListBank() (res ResponseBankList, err error)
+	CheckBankAccount(request RequestBankInquiry) (res ResponseBankInquiry, err error)
+	Withdraw(request RequestWithdraw) (res ResponseWithdraw, err error)
+	CheckWithdraw(withdrawID int) (res ResponseWithdraw, err error)
+	TransferBalance(request RequestTransferBalance) (res ResponseTransfer, err error)
+	TransferMutation(transactionID int) (trx Transaction, err error)
+	RegisterMember(request RequestRegisterMember) (res ResponseMember, err error)
+	GetMember(account string) (res ResponseMember, err error)
+	GetMemberBalance(account string) (res ResponseBalance, err error)
+	MemberHistoryTransaction(account string, request RequestTransactionHistory) (res ResponseTransaction, err error)
+	ReleaseEscrow(transactionID int) (res ResponseEscrow, err error)
+	RefundEscrow(transactionID int) (res ResponseEscrow, err error)
+	EscrowTransactions(request RequestTransactionHistory) (trx []Transaction, err error)
+	SplitMutations(transactionID int, request RequestTransactionHistory) (trx []Transaction, err error)
+	WaitForTransaction(ctx context.Context, transactionID int, opts WaitOptions) (res ResponseCheck, err error)
 	AssignCredential(apiKey, virtualAccount string, env EnvironmentType)
 }
 
@@ -27,6 +43,20 @@ type Client struct {
 	ApiKey         string
 	VirtualAccount string
 	EnvApi         EnvironmentType
+
+	// HTTPClient sends the API requests. When nil, a shared client with a 30 second timeout is used.
+	HTTPClient *http.Client
+
+	// OnSchemaDrift, when set, is called for every response whose body has fields the response
+	// type does not map, or lacks fields it does. It lets API changes be noticed before the SDK is updated.
+	OnSchemaDrift func(drift SchemaDrift)
+
+	// lastBalance is the merchant balance from the latest GetBalance call, used to guard Withdraw.
+	// reservedBalance is the part of it set aside for withdrawals in flight, and balanceGen counts
+	// the withdrawals subtracted from it.
+	lastBalance     *Money
+	reservedBalance Money
+	balanceGen      uint64
 }
 
 func NewClient() ClientApi {
@@ -48,6 +78,16 @@ func (c *Client) AssignCredential(apiKey, virtualAccount string, env Environment
 
 var defHTTPTimeout = 30 * time.Second
 
+// defHTTPClient is used by clients without an HTTPClient. Its timeout is set once here,
+// so that concurrent requests do not write to a shared client.
+var defHTTPClient = &http.Client{Timeout: defHTTPTimeout}
+
+// signature signs body with the credentials of the client. Only the credential fields are
+// copied, so that signing does not read state written by concurrent calls.
+func (c *Client) signature(body []byte) string {
+	return GenerateSignature(string(body), "POST", Client{ApiKey: c.ApiKey, VirtualAccount: c.VirtualAccount})
+}
+
 // CallApi sends a POST request to the specified URL with the provided signature and body.
 // It constructs an HTTP request with the necessary headers and makes a request to the iPaymu API.
 //
@@ -71,8 +111,10 @@ func (c *Client) CallApi(url *url.URL, signature string, body []byte) ([]byte, e
 		Body: reqBody,
 	}
 
-	httpClient := http.DefaultClient
-	httpClient.Timeout = defHTTPTimeout
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = defHTTPClient
+	}
 	resp, err := httpClient.Do(req)
 
 	if err != nil {
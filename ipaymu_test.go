@@ -6,7 +6,7 @@ import (
 )
 
 func TestClient_DirectPaymentVA(t *testing.T) {
-	cl := NewClient()
+	cl := &Client{}
 	cl.EnvApi = Sandbox
 	cl.VirtualAccount = "1179000899"
 	cl.ApiKey = "QbGcoO0Qds9sQFDmY0MWg1Tq.xtuh1"
@@ -24,7 +24,7 @@ func TestClient_DirectPaymentVA(t *testing.T) {
 	tests := []struct {
 		name    string
 		args    args
-		want    int64
+		want    FlexInt
 		wantErr bool
 	}{
 		{
@@ -50,7 +50,7 @@ func TestClient_DirectPaymentVA(t *testing.T) {
 }
 
 func TestClient_RedirectPayment(t *testing.T) {
-	cl := NewClient()
+	cl := &Client{}
 	cl.EnvApi = Sandbox
 	cl.VirtualAccount = "1179002284460840"
 	cl.ApiKey = "CDC1AD1E-A19C-40E6-998D-9736BF4E42FA"
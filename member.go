@@ -0,0 +1,101 @@
+package ipaymu_go_api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// RegisterMember registers a new member (sub-merchant) account under the merchant.
+//
+// Parameters:
+// - request: A RequestRegisterMember struct containing the member's name, phone, email, and password.
+//
+// Return values:
+// - res: A ResponseMember struct containing the created member account, including its virtual account.
+// - err: An error if any occurred during the API call or response parsing.
+//
+// If the API call fails or the status code is not 200, the function returns an error with the corresponding message.
+func (c *Client) RegisterMember(request RequestRegisterMember) (res ResponseMember, err error) {
+	uri, _ := url.Parse(fmt.Sprintf("%s/api/v2/register", c.EnvApi))
+	jsonBody, _ := json.Marshal(request)
+	signature := c.signature(jsonBody)
+	api, err := c.CallApi(uri, signature, jsonBody)
+	if err != nil {
+		return
+	}
+
+	err = c.decodeResponse(uri, api, &res)
+	if err != nil {
+		return
+	}
+
+	if res.Status != 200 {
+		return res, fmt.Errorf("%s", res.Message)
+	}
+
+	return
+}
+
+// GetMember retrieves the details of a member account.
+//
+// account: The virtual account of the member, as returned by RegisterMember.
+//
+// Return values:
+// - res: A ResponseMember struct containing the member details.
+// - err: An error if any occurred during the API call or response parsing.
+//
+// If the API call fails or the status code is not 200, the function returns an error with the corresponding message.
+func (c *Client) GetMember(account string) (res ResponseMember, err error) {
+	uri, _ := url.Parse(fmt.Sprintf("%s/api/v2/member", c.EnvApi))
+	jsonBody, _ := json.Marshal(map[string]string{"account": account})
+	signature := c.signature(jsonBody)
+	api, err := c.CallApi(uri, signature, jsonBody)
+	if err != nil {
+		return
+	}
+
+	err = c.decodeResponse(uri, api, &res)
+	if err != nil {
+		return
+	}
+
+	if res.Status != 200 {
+		return res, fmt.Errorf("%s", res.Message)
+	}
+
+	return
+}
+
+// GetMemberBalance retrieves the balance of a member account.
+//
+// It works like GetBalance but queries the given member account instead of the client's virtual account,
+// and does not update the balance used to guard Withdraw.
+func (c *Client) GetMemberBalance(account string) (res ResponseBalance, err error) {
+	uri, _ := url.Parse(fmt.Sprintf("%s/api/v2/balance", c.EnvApi))
+	jsonBody, _ := json.Marshal(map[string]string{"account": account})
+	signature := c.signature(jsonBody)
+	api, err := c.CallApi(uri, signature, jsonBody)
+	if err != nil {
+		return
+	}
+
+	err = c.decodeResponse(uri, api, &res)
+	if err != nil {
+		return
+	}
+
+	if res.Status != 200 {
+		return res, fmt.Errorf("%s", res.Message)
+	}
+
+	return
+}
+
+// MemberHistoryTransaction retrieves the transaction history of a member account.
+//
+// It sets the Account filter of the request to the given member account and calls HistoryTransaction.
+func (c *Client) MemberHistoryTransaction(account string, request RequestTransactionHistory) (res ResponseTransaction, err error) {
+	request.Account = &account
+	return c.HistoryTransaction(request)
+}
@@ -0,0 +1,87 @@
+package ipaymu_go_api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestClient_Member(t *testing.T) {
+	var mu sync.Mutex
+	bodies := make(map[string]map[string]interface{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		bodies[r.URL.Path] = body
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/api/v2/register", "/api/v2/member":
+			w.Write([]byte(`{"Status":200,"Data":{"Va":"1179000001","Name":"Budi","Email":"budi@example.com","Phone":"081234567890","Status":"active","CreatedDate":"2024-05-01 09:00:00"},"Message":"success"}`))
+		case "/api/v2/balance":
+			w.Write([]byte(`{"Status":200,"Data":{"Va":"1179000001","MerchantBalance":0,"MemberBalance":75000},"Message":"success"}`))
+		case "/api/v2/history":
+			w.Write([]byte(`{"Status":200,"Success":true,"Message":"success","Data":{"Transaction":[{"TransactionId":1,"Receiver":"1179000001"}]}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	cl := &Client{}
+	cl.AssignCredential("api-key", "1179000899", EnvironmentType(srv.URL))
+
+	t.Run("register", func(t *testing.T) {
+		res, err := cl.RegisterMember(*NewRequestRegisterMember("Budi", "081234567890", "budi@example.com", "secret"))
+		if err != nil {
+			t.Fatalf("RegisterMember() error = %v", err)
+		}
+		if res.Data.Va != "1179000001" || !res.Data.CreatedDate.Valid {
+			t.Errorf("RegisterMember() = %+v", res.Data)
+		}
+		if body := bodies["/api/v2/register"]; body["name"] != "Budi" || body["password"] != "secret" {
+			t.Errorf("RegisterMember() sent %v", body)
+		}
+	})
+
+	t.Run("get member", func(t *testing.T) {
+		if _, err := cl.GetMember("1179000001"); err != nil {
+			t.Fatalf("GetMember() error = %v", err)
+		}
+		if body := bodies["/api/v2/member"]; body["account"] != "1179000001" {
+			t.Errorf("GetMember() sent %v, want account 1179000001", body)
+		}
+	})
+
+	t.Run("member balance does not guard withdraw", func(t *testing.T) {
+		res, err := cl.GetMemberBalance("1179000001")
+		if err != nil {
+			t.Fatalf("GetMemberBalance() error = %v", err)
+		}
+		if res.Data.MemberBalance != 75000 {
+			t.Errorf("GetMemberBalance() MemberBalance = %s, want Rp75.000", res.Data.MemberBalance)
+		}
+		if body := bodies["/api/v2/balance"]; body["account"] != "1179000001" {
+			t.Errorf("GetMemberBalance() sent %v, want account 1179000001", body)
+		}
+		if balance, ok := cl.knownBalance(); ok {
+			t.Errorf("GetMemberBalance() set lastBalance to %s", balance)
+		}
+	})
+
+	t.Run("member history", func(t *testing.T) {
+		res, err := cl.MemberHistoryTransaction("1179000001", *NewRequestTransactionHistory())
+		if err != nil {
+			t.Fatalf("MemberHistoryTransaction() error = %v", err)
+		}
+		if len(res.Data.Transaction) != 1 {
+			t.Errorf("MemberHistoryTransaction() = %+v, want one transaction", res.Data)
+		}
+		if body := bodies["/api/v2/history"]; body["account"] != "1179000001" {
+			t.Errorf("MemberHistoryTransaction() sent %v, want account 1179000001", body)
+		}
+	})
+}
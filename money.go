@@ -0,0 +1,184 @@
+package ipaymu_go_api
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount of Indonesian Rupiah in whole rupiah.
+//
+// It is used for every amount, price, fee, and balance sent to or received from iPaymu.
+// Arithmetic on Money is exact; conversions that produce fractions of a rupiah round
+// according to a RoundingMode. In JSON it is written as an integer and read from numbers
+// or numeric strings; amounts with a fraction of a rupiah are rejected rather than rounded.
+type Money int64
+
+// RoundingMode decides how fractions of a rupiah are rounded.
+type RoundingMode int8
+
+const (
+	// RoundHalfUp rounds to the nearest rupiah, halves away from zero.
+	RoundHalfUp RoundingMode = 0
+	// RoundDown truncates towards zero.
+	RoundDown RoundingMode = 1
+	// RoundUp rounds away from zero.
+	RoundUp RoundingMode = 2
+)
+
+// MoneyFromFloat converts a float amount to Money, rounding half up.
+func MoneyFromFloat(f float64) Money {
+	return Money(math.Round(f))
+}
+
+// ParseMoney parses a decimal amount such as "100000" or "100000.50", rounding half up.
+func ParseMoney(s string) (Money, error) {
+	r, err := parseAmount(s)
+	if err != nil {
+		return 0, err
+	}
+	return roundRat(r, RoundHalfUp)
+}
+
+// floatArtefact is the largest fraction of a rupiah that is taken for noise from a float
+// encoding, such as 104000.00000000001, rather than for a real fraction.
+var floatArtefact = big.NewRat(1, 1000000)
+
+// parseExactMoney parses a decimal amount that must be whole rupiah, apart from float noise.
+func parseExactMoney(s string) (Money, error) {
+	r, err := parseAmount(s)
+	if err != nil {
+		return 0, err
+	}
+	m, err := roundRat(r, RoundHalfUp)
+	if err != nil {
+		return 0, err
+	}
+	diff := new(big.Rat).Sub(r, new(big.Rat).SetInt64(int64(m)))
+	if diff.Abs(diff).Cmp(floatArtefact) > 0 {
+		return 0, fmt.Errorf("amount %q has a fraction of a rupiah", s)
+	}
+	return m, nil
+}
+
+// parseAmount parses a decimal amount exactly.
+func parseAmount(s string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	return r, nil
+}
+
+// Add returns m + o.
+func (m Money) Add(o Money) Money {
+	return m + o
+}
+
+// Sub returns m - o.
+func (m Money) Sub(o Money) Money {
+	return m - o
+}
+
+// Mul returns m multiplied by n.
+func (m Money) Mul(n int64) Money {
+	return m * Money(n)
+}
+
+// Percent returns p percent of m, rounded with mode. p is taken as the exact decimal it prints as,
+// so Percent(2.5, RoundHalfUp) of Rp10.001 is Rp250. A p that is NaN or infinite yields zero.
+func (m Money) Percent(p float64, mode RoundingMode) Money {
+	rate, ok := new(big.Rat).SetString(strconv.FormatFloat(p, 'f', -1, 64))
+	if !ok {
+		return 0
+	}
+	r := new(big.Rat).SetInt64(int64(m))
+	r.Mul(r, rate)
+	r.Quo(r, big.NewRat(100, 1))
+	res, _ := roundRat(r, mode)
+	return res
+}
+
+// Float64 returns the amount as a float64.
+func (m Money) Float64() float64 {
+	return float64(m)
+}
+
+// String formats the amount in Rupiah, such as "Rp100.000" or "-Rp2.500".
+func (m Money) String() string {
+	sign := ""
+	n := int64(m)
+	digits := strconv.FormatInt(n, 10)
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+	return sign + "Rp" + b.String()
+}
+
+// MarshalJSON implements json.Marshaler, encoding the amount as an integer.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(int64(m), 10)), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts numbers and numeric strings;
+// null and the empty string decode to zero. A fraction of a rupiah, such as 4440.50, is an error
+// so that the amount is never silently changed; trailing zeros and float noise are accepted.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*m = 0
+		return nil
+	}
+
+	s, err := flexString(data)
+	if err != nil {
+		return err
+	}
+	if s == "" {
+		*m = 0
+		return nil
+	}
+
+	parsed, err := parseExactMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// roundRat rounds r to whole rupiah with mode.
+func roundRat(r *big.Rat, mode RoundingMode) (Money, error) {
+	num, den := new(big.Int).Set(r.Num()), r.Denom()
+	neg := num.Sign() < 0
+	num.Abs(num)
+
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() != 0 {
+		switch mode {
+		case RoundUp:
+			q.Add(q, big.NewInt(1))
+		case RoundHalfUp:
+			if rem.Mul(rem, big.NewInt(2)).Cmp(den) >= 0 {
+				q.Add(q, big.NewInt(1))
+			}
+		}
+	}
+	if neg {
+		q.Neg(q)
+	}
+	if !q.IsInt64() {
+		return 0, fmt.Errorf("amount %s out of range", r.FloatString(2))
+	}
+	return Money(q.Int64()), nil
+}
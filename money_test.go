@@ -0,0 +1,102 @@
+package ipaymu_go_api
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestMoney_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Money
+		wantErr bool
+	}{
+		{name: "integer", data: `100000`, want: 100000},
+		{name: "half rupiah", data: `4000.5`, wantErr: true},
+		{name: "fraction in string", data: `"4440.50"`, wantErr: true},
+		{name: "float artefact", data: `104000.00000000001`, want: 104000},
+		{name: "float artefact below", data: `103999.99999999999`, want: 104000},
+		{name: "numeric string", data: `"2500.00"`, want: 2500},
+		{name: "negative", data: `-1500`, want: -1500},
+		{name: "negative fraction", data: `-1500.5`, wantErr: true},
+		{name: "null", data: `null`, want: 0},
+		{name: "empty string", data: `""`, want: 0},
+		{name: "not a number", data: `"free"`, wantErr: true},
+		{name: "object", data: `{}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Money
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_Percent(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount Money
+		p      float64
+		mode   RoundingMode
+		want   Money
+	}{
+		{name: "exact", amount: 100000, p: 10, mode: RoundHalfUp, want: 10000},
+		{name: "half up", amount: 10001, p: 2.5, mode: RoundHalfUp, want: 250},
+		{name: "half up on half", amount: 100, p: 0.5, mode: RoundHalfUp, want: 1},
+		{name: "down", amount: 10099, p: 1, mode: RoundDown, want: 100},
+		{name: "up", amount: 10001, p: 1, mode: RoundUp, want: 101},
+		{name: "fraction of percent", amount: 1000000, p: 0.7, mode: RoundHalfUp, want: 7000},
+		{name: "negative half up", amount: -100, p: 0.5, mode: RoundHalfUp, want: -1},
+		{name: "NaN", amount: 100000, p: math.NaN(), mode: RoundHalfUp, want: 0},
+		{name: "infinite", amount: 100000, p: math.Inf(1), mode: RoundHalfUp, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.amount.Percent(tt.p, tt.mode); got != tt.want {
+				t.Errorf("Percent() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	tests := []struct {
+		amount Money
+		want   string
+	}{
+		{amount: 0, want: "Rp0"},
+		{amount: 500, want: "Rp500"},
+		{amount: 100000, want: "Rp100.000"},
+		{amount: 1234567, want: "Rp1.234.567"},
+		{amount: -2500, want: "-Rp2.500"},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestMoney_MarshalJSON(t *testing.T) {
+	request := NewRequestTransferBalance("1179000001", 150000)
+	body, err := json.Marshal(request)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var got map[string]json.RawMessage
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if string(got["amount"]) != "150000" {
+		t.Errorf("amount = %s, want 150000", got["amount"])
+	}
+}
@@ -23,13 +23,13 @@ import (
 func (c *Client) ListPaymentMethod() (res ResponseListPayment, err error) {
     url, _ := url.Parse(fmt.Sprintf("%s/api/v2/payment-method-list", c.EnvApi))
     jsonBody, _ := json.Marshal(map[string]bool{"request": true})
-    signature := fmt.Sprintf("%s", GenerateSignature(string(jsonBody), "POST", *c))
+    signature := fmt.Sprintf("%s", c.signature(jsonBody))
     api, err := c.CallApi(url, signature, jsonBody)
     if err != nil {
         return
     }
 
-    err = json.Unmarshal(api, &res)
+    err = c.decodeResponse(url, api, &res)
     if err != nil {
         return
     }
@@ -55,16 +55,22 @@ func (c *Client) ListPaymentMethod() (res ResponseListPayment, err error) {
 // sends the request with the signature, and processes the response. If the request is successful (status code 200),
 // it unmarshals the response into a Response struct and returns it along with no error. If the request fails
 // (status code other than 200), it returns an error containing the error message from the response.
+//
+// Split rules on the request are validated against Amount before anything is sent.
 func (c *Client) DirectPaymentVA(request RequestDirectVA) (res Response, err error) {
+    if err = ValidateSplit(request.Split, request.Amount); err != nil {
+        return Response{}, err
+    }
+
     url, _ := url.Parse(fmt.Sprintf("%s/api/v2/payment/direct", c.EnvApi))
     jsonBody, _ := json.Marshal(request)
-    signature := fmt.Sprintf("%s", GenerateSignature(string(jsonBody), "POST", *c))
+    signature := fmt.Sprintf("%s", c.signature(jsonBody))
     api, err := c.CallApi(url, signature, jsonBody)
     if err != nil {
         return Response{}, err
     }
 
-    err = json.Unmarshal(api, &res)
+    err = c.decodeResponse(url, api, &res)
     if err != nil {
         return Response{}, err
     }
@@ -90,16 +96,22 @@ func (c *Client) DirectPaymentVA(request RequestDirectVA) (res Response, err err
 // sends the request with the signature, and processes the response. If the request is successful (status code 200),
 // it unmarshals the response into a Response struct and returns it along with no error. If the request fails
 // (status code other than 200), it returns an error containing the error message from the response.
+//
+// Split rules on the request are validated against Amount before anything is sent.
 func (c *Client) DirectPaymentConStore(request RequestDirectConStore) (res Response, err error) {
+    if err = ValidateSplit(request.Split, request.Amount); err != nil {
+        return Response{}, err
+    }
+
     url, _ := url.Parse(fmt.Sprintf("%s/api/v2/payment/direct", c.EnvApi))
     jsonBody, _ := json.Marshal(request)
-    signature := fmt.Sprintf("%s", GenerateSignature(string(jsonBody), "POST", *c))
+    signature := fmt.Sprintf("%s", c.signature(jsonBody))
     api, err := c.CallApi(url, signature, jsonBody)
     if err != nil {
         return Response{}, err
     }
 
-    err = json.Unmarshal(api, &res)
+    err = c.decodeResponse(url, api, &res)
     if err != nil {
         return Response{}, err
     }
@@ -125,16 +137,22 @@ func (c *Client) DirectPaymentConStore(request RequestDirectConStore) (res Respo
 // sends the request with the signature, and processes the response. If the request is successful (status code 200),
 // it unmarshals the response into a Response struct and returns it along with no error. If the request fails
 // (status code other than 200), it returns an error containing the error message from the response.
+//
+// Split rules on the request are validated against Amount before anything is sent.
 func (c *Client) DirectPaymentCOD(request RequestDirectCOD) (res Response, err error) {
+    if err = ValidateSplit(request.Split, request.Amount); err != nil {
+        return Response{}, err
+    }
+
     url, _ := url.Parse(fmt.Sprintf("%s/api/v2/payment/direct", c.EnvApi))
     jsonBody, _ := json.Marshal(request)
-    signature := fmt.Sprintf("%s", GenerateSignature(string(jsonBody), "POST", *c))
+    signature := fmt.Sprintf("%s", c.signature(jsonBody))
     api, err := c.CallApi(url, signature, jsonBody)
     if err != nil {
         return Response{}, err
     }
 
-    err = json.Unmarshal(api, &res)
+    err = c.decodeResponse(url, api, &res)
     if err != nil {
         return Response{}, err
     }
@@ -168,16 +186,22 @@ func (c *Client) DirectPaymentCOD(request RequestDirectCOD) (res Response, err e
 //
 // If any error occurs during the request or response processing, the function returns
 // an empty Response and the corresponding error.
+//
+// Split rules on the request are validated against TotalAmount before anything is sent.
 func (c *Client) RedirectPayment(request RequestRedirect) (res Response, err error) {
+    if err = ValidateSplit(request.Split, request.TotalAmount()); err != nil {
+        return Response{}, err
+    }
+
     url, _ := url.Parse(fmt.Sprintf("%s/api/v2/payment/", c.EnvApi))
     jsonBody, _ := json.Marshal(request)
-    signature := fmt.Sprintf("%s", GenerateSignature(string(jsonBody), "POST", *c))
+    signature := fmt.Sprintf("%s", c.signature(jsonBody))
     api, err := c.CallApi(url, signature, jsonBody)
     if err != nil {
         return Response{}, err
     }
 
-    err = json.Unmarshal(api, &res)
+    err = c.decodeResponse(url, api, &res)
     if err != nil {
         return Response{}, err
     }
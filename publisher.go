@@ -0,0 +1,196 @@
+package ipaymu_go_api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PaymentEvent is the normalized form of a callback, published to downstream services.
+type PaymentEvent struct {
+	TransactionID int           `json:"transactionId"`
+	SessionID     string        `json:"sessionId"`
+	ReferenceID   string        `json:"referenceId"`
+	Status        PaymentStatus `json:"status"`
+	StatusText    string        `json:"statusText"`
+	Amount        Money         `json:"amount"`
+	Fee           Money         `json:"fee"`
+	Via           string        `json:"via"`
+	Channel       string        `json:"channel"`
+	PaymentNo     string        `json:"paymentNo"`
+	PaidAt        *time.Time    `json:"paidAt,omitempty"`
+	Verified      bool          `json:"verified"`
+	ReceivedAt    time.Time     `json:"receivedAt"`
+}
+
+// NewPaymentEvent normalizes a callback event.
+func NewPaymentEvent(event *CallbackEvent) PaymentEvent {
+	cb := event.Callback
+	return PaymentEvent{
+		TransactionID: cb.TrxID,
+		SessionID:     cb.SID,
+		ReferenceID:   cb.ReferenceID,
+		Status:        event.Status,
+		StatusText:    cb.Status,
+		Amount:        cb.Amount,
+		Fee:           cb.Fee,
+		Via:           cb.Via,
+		Channel:       cb.Channel,
+		PaymentNo:     cb.PaymentNo,
+		Verified:      event.Verified,
+		PaidAt:        cb.PaidAt.Ptr(),
+		ReceivedAt:    time.Now().In(jakarta),
+	}
+}
+
+// Publisher delivers payment events to downstream services.
+type Publisher interface {
+	Publish(ctx context.Context, event PaymentEvent) error
+}
+
+// MemoryPublisher is a Publisher that keeps events in memory and forwards them to subscribers.
+// It is meant for tests and single-process setups.
+type MemoryPublisher struct {
+	mu          sync.Mutex
+	events      []PaymentEvent
+	subscribers []chan PaymentEvent
+}
+
+// NewMemoryPublisher creates an empty MemoryPublisher.
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+// Publish records the event and sends it to every subscriber. Subscribers whose buffer
+// is full miss the event rather than blocking the publisher.
+func (p *MemoryPublisher) Publish(ctx context.Context, event PaymentEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = append(p.events, event)
+	for _, ch := range p.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+	return nil
+}
+
+// Subscribe returns a channel receiving every event published from now on.
+func (p *MemoryPublisher) Subscribe(buffer int) <-chan PaymentEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ch := make(chan PaymentEvent, buffer)
+	p.subscribers = append(p.subscribers, ch)
+	return ch
+}
+
+// Events returns the events published so far.
+func (p *MemoryPublisher) Events() []PaymentEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]PaymentEvent(nil), p.events...)
+}
+
+// WebhookPublisher is a Publisher that posts every event as JSON to a list of URLs.
+//
+// Each URL is tried up to MaxRetries+1 times, waiting Backoff before the first retry
+// and doubling the wait after every attempt. Responses other than 2xx count as failures.
+type WebhookPublisher struct {
+	URLs       []string
+	Header     http.Header
+	Client     *http.Client
+	MaxRetries int
+	Backoff    time.Duration
+}
+
+// NewWebhookPublisher creates a WebhookPublisher for urls with 3 retries starting at 500ms.
+func NewWebhookPublisher(urls ...string) *WebhookPublisher {
+	return &WebhookPublisher{
+		URLs:       urls,
+		Client:     &http.Client{Timeout: defHTTPTimeout},
+		MaxRetries: 3,
+		Backoff:    500 * time.Millisecond,
+	}
+}
+
+// Publish posts the event to all URLs concurrently and returns an error listing every URL
+// that still failed after its retries.
+func (p *WebhookPublisher) Publish(ctx context.Context, event PaymentEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(p.URLs))
+	for i, u := range p.URLs {
+		wg.Add(1)
+		go func(i int, u string) {
+			defer wg.Done()
+			errs[i] = p.post(ctx, u, body)
+		}(i, u)
+	}
+	wg.Wait()
+
+	var failed []string
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", p.URLs[i], err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("publish failed for %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+func (p *WebhookPublisher) post(ctx context.Context, u string, body []byte) (err error) {
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	wait := p.Backoff
+	for attempt := 0; attempt <= p.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+			wait *= 2
+		}
+
+		var req *http.Request
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		for key, values := range p.Header {
+			req.Header[key] = values
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		var resp *http.Response
+		resp, err = client.Do(req)
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		err = fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return err
+}
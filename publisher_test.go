@@ -0,0 +1,180 @@
+package ipaymu_go_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWebhookPublisher_Publish(t *testing.T) {
+	var attempts int32
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer flaky.Close()
+
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer down.Close()
+
+	tests := []struct {
+		name    string
+		urls    []string
+		wantErr bool
+	}{
+		{
+			name:    "succeeds after retries",
+			urls:    []string{flaky.URL},
+			wantErr: false,
+		},
+		{
+			name:    "fails after retries",
+			urls:    []string{down.URL},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewWebhookPublisher(tt.urls...)
+			p.Backoff = time.Millisecond
+			err := p.Publish(context.Background(), PaymentEvent{TransactionID: 96748, Status: Success})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Publish() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+type failingPublisher struct {
+	calls int32
+}
+
+func (p *failingPublisher) Publish(ctx context.Context, event PaymentEvent) error {
+	atomic.AddInt32(&p.calls, 1)
+	return errors.New("broker down")
+}
+
+// newCheckServer returns a client whose CheckTransaction reports the transaction with the current *status.
+func newCheckServer(t *testing.T, status *int32) *Client {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			TransactionID int `json:"transactionId"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		fmt.Fprintf(w, `{"Status":200,"Data":{"TransactionId":%d,"Status":%d},"Message":"success"}`, request.TransactionID, atomic.LoadInt32(status))
+	}))
+	t.Cleanup(srv.Close)
+
+	cl := &Client{}
+	cl.AssignCredential("api-key", "1179000899", EnvironmentType(srv.URL))
+	return cl
+}
+
+func TestCallbackHandler_Publish(t *testing.T) {
+	var funcCalls, publishErrors int32
+	publisher := &failingPublisher{}
+	h := NewCallbackHandler(func(r *http.Request, event *CallbackEvent) error {
+		atomic.AddInt32(&funcCalls, 1)
+		return nil
+	})
+	status := int32(Success)
+	h.Verify = VerifyReject
+	h.Client = newCheckServer(t, &status)
+	h.Dedup = NewMemoryDedupStore()
+	h.Publisher = publisher
+	h.OnPublishError = func(event PaymentEvent, err error) {
+		atomic.AddInt32(&publishErrors, 1)
+	}
+
+	// The second post is the retry iPaymu would send had the first one failed.
+	wantCodes := []int{http.StatusOK, http.StatusOK}
+	for i, want := range wantCodes {
+		req := httptest.NewRequest(http.MethodPost, "/notify", strings.NewReader("trx_id=96748&status=berhasil&status_code=1"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		h.Wait()
+		if rec.Code != want {
+			t.Errorf("post %d: status = %d, want %d", i, rec.Code, want)
+		}
+	}
+
+	if funcCalls != 1 || publisher.calls != 1 || publishErrors != 1 {
+		t.Errorf("Func, Publish, OnPublishError calls = %d, %d, %d, want 1, 1, 1", funcCalls, publisher.calls, publishErrors)
+	}
+}
+
+func TestCallbackHandler_PublishVerifiedOnly(t *testing.T) {
+	publisher := NewMemoryPublisher()
+	h := NewCallbackHandler(nil)
+	h.Publisher = publisher
+
+	req := httptest.NewRequest(http.MethodPost, "/notify", strings.NewReader("trx_id=96748&status=berhasil&status_code=1"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	h.Wait()
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("ServeHTTP() code = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if events := publisher.Events(); len(events) != 0 {
+		t.Errorf("unverified callback published %+v", events)
+	}
+}
+
+// slowPublisher holds back pending events, so that later events would overtake them if
+// publishing were not ordered.
+type slowPublisher struct {
+	MemoryPublisher
+}
+
+func (p *slowPublisher) Publish(ctx context.Context, event PaymentEvent) error {
+	if event.Status == Pending {
+		time.Sleep(20 * time.Millisecond)
+	}
+	return p.MemoryPublisher.Publish(ctx, event)
+}
+
+func TestCallbackHandler_PublishOrder(t *testing.T) {
+	publisher := &slowPublisher{}
+	status := int32(Pending)
+	h := NewCallbackHandler(nil)
+	h.Verify = VerifyReject
+	h.Client = newCheckServer(t, &status)
+	h.Publisher = publisher
+
+	post := func(code PaymentStatus) {
+		req := httptest.NewRequest(http.MethodPost, "/notify", strings.NewReader(fmt.Sprintf("trx_id=96748&status_code=%d", code)))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("ServeHTTP(%s) code = %d, want %d", code, rec.Code, http.StatusOK)
+		}
+	}
+	post(Pending)
+	atomic.StoreInt32(&status, int32(Success))
+	post(Success)
+	h.Wait()
+
+	var got []PaymentStatus
+	for _, event := range publisher.Events() {
+		got = append(got, event.Status)
+	}
+	if len(got) != 2 || got[0] != Pending || got[1] != Success {
+		t.Errorf("published statuses = %v, want [%s %s]", got, Pending, Success)
+	}
+}
@@ -0,0 +1,136 @@
+package ipaymu_go_api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// RedirectResult holds the parameters iPaymu appends to the returnUrl or cancelUrl
+// of a redirect payment when the buyer comes back to the merchant site.
+type RedirectResult struct {
+	SessionID     string
+	TransactionID int
+	ReferenceID   string
+	Status        string
+	PaymentStatus PaymentStatus
+	Canceled      bool
+
+	// Transaction is set when the result was confirmed with CheckTransaction.
+	// PaymentStatus is then taken from the transaction instead of the query string.
+	// A transaction whose reference or session ID differs from the query is not used.
+	Transaction *ResponseCheck
+}
+
+// ParseRedirectResult reads the query parameters of a returnUrl or cancelUrl request.
+//
+// The query string is not authenticated, so the status should only be trusted for display
+// unless it is confirmed with CheckTransaction, as RedirectHandler does when it has a Client.
+func ParseRedirectResult(r *http.Request) (res RedirectResult, err error) {
+	query := r.URL.Query()
+	res.SessionID = query.Get("sid")
+	res.ReferenceID = query.Get("reference_id")
+	res.Status = query.Get("status")
+
+	if v := query.Get("trx_id"); v != "" {
+		if res.TransactionID, err = strconv.Atoi(v); err != nil {
+			return res, fmt.Errorf("invalid trx_id %q", v)
+		}
+	}
+
+	res.PaymentStatus = Pending
+	if v := query.Get("status_code"); v != "" {
+		code, err := strconv.ParseInt(v, 10, 8)
+		if err != nil {
+			return res, fmt.Errorf("invalid status_code %q", v)
+		}
+		res.PaymentStatus = PaymentStatus(code)
+	} else if status, ok := statusText[strings.ToLower(res.Status)]; ok {
+		res.PaymentStatus = status
+	}
+
+	return
+}
+
+// RedirectFunc renders the page for a buyer returning from iPaymu. err is set when the
+// parameters could not be parsed or confirmed; result then holds what could be read.
+type RedirectFunc func(w http.ResponseWriter, r *http.Request, result *RedirectResult, err error)
+
+// RedirectHandler is an http.Handler for the returnUrl or cancelUrl of redirect payments.
+type RedirectHandler struct {
+	Func     RedirectFunc
+	Canceled bool
+
+	// Client, when set, is used to confirm the result with CheckTransaction.
+	Client ClientApi
+}
+
+// NewReturnHandler creates a RedirectHandler for the returnUrl of redirect payments.
+func NewReturnHandler(fn RedirectFunc) *RedirectHandler {
+	return &RedirectHandler{
+		Func: fn,
+	}
+}
+
+// NewCancelHandler creates a RedirectHandler for the cancelUrl of redirect payments.
+// Results it passes to fn are marked Canceled.
+func NewCancelHandler(fn RedirectFunc) *RedirectHandler {
+	return &RedirectHandler{
+		Func:     fn,
+		Canceled: true,
+	}
+}
+
+// ServeHTTP implements http.Handler. It parses the result, confirms it when a Client is set,
+// and passes it to Func. Without a Func it replies 200 OK, or 400 Bad Request when the result
+// could not be read or confirmed.
+func (h *RedirectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	result, err := ParseRedirectResult(r)
+	if h.Canceled {
+		result.Canceled = true
+		if result.Status == "" {
+			result.PaymentStatus = Cancel
+		}
+	}
+
+	if err == nil && h.Client != nil {
+		if result.TransactionID == 0 {
+			err = fmt.Errorf("missing trx_id")
+		} else {
+			var check ResponseCheck
+			if check, err = h.Client.CheckTransaction(result.TransactionID); err == nil {
+				if err = CompareRedirect(result, check); err == nil {
+					result.Transaction = &check
+					result.PaymentStatus = check.Data.Status
+				}
+			}
+		}
+	}
+
+	if h.Func == nil {
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+	h.Func(w, r, &result, err)
+}
+
+// CompareRedirect checks that the transaction returned by CheckTransaction belongs to the redirect result.
+//
+// The query string can be edited by the buyer, so the reference ID and session ID must match the
+// transaction; each is only compared when the query carries it, and at least one of them is required.
+// It returns an error describing the first difference.
+func CompareRedirect(result RedirectResult, check ResponseCheck) error {
+	if result.ReferenceID == "" && result.SessionID == "" {
+		return fmt.Errorf("missing reference_id and sid")
+	}
+	if ref := check.Data.ReferenceId.String(); result.ReferenceID != "" && result.ReferenceID != ref {
+		return fmt.Errorf("reference id %q does not match transaction reference id %q", result.ReferenceID, ref)
+	}
+	if sid := check.Data.SessionId.String(); result.SessionID != "" && result.SessionID != sid {
+		return fmt.Errorf("session id %q does not match transaction session id %q", result.SessionID, sid)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package ipaymu_go_api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseRedirectResult(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantTrxID  int
+		wantStatus PaymentStatus
+		wantErr    bool
+	}{
+		{
+			name:       "status code wins over text",
+			query:      "?trx_id=96748&sid=abc-123&status=pending&status_code=1&reference_id=trx-123",
+			wantTrxID:  96748,
+			wantStatus: Success,
+		},
+		{
+			name:       "status text without code",
+			query:      "?trx_id=96748&status=berhasil",
+			wantTrxID:  96748,
+			wantStatus: Success,
+		},
+		{
+			name:       "unknown text defaults to pending",
+			query:      "?trx_id=96748&status=processing",
+			wantTrxID:  96748,
+			wantStatus: Pending,
+		},
+		{
+			name:    "invalid trx_id",
+			query:   "?trx_id=abc&status=berhasil",
+			wantErr: true,
+		},
+		{
+			name:      "invalid status_code",
+			query:     "?trx_id=96748&status_code=x",
+			wantTrxID: 96748,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRedirectResult(httptest.NewRequest(http.MethodGet, "/return"+tt.query, nil))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRedirectResult() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.TransactionID != tt.wantTrxID || got.PaymentStatus != tt.wantStatus {
+				t.Errorf("ParseRedirectResult() = %+v, want trx %d status %s", got, tt.wantTrxID, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRedirectHandler(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Status":200,"Data":{"TransactionId":96748,"SessionId":"abc-123","ReferenceId":"trx-123","Amount":100000,"Status":0},"Message":"success"}`))
+	}))
+	defer srv.Close()
+
+	cl := &Client{}
+	cl.AssignCredential("api-key", "1179000899", EnvironmentType(srv.URL))
+
+	tests := []struct {
+		name         string
+		canceled     bool
+		client       ClientApi
+		query        string
+		wantStatus   PaymentStatus
+		wantCanceled bool
+		wantChecked  bool
+		wantErr      bool
+	}{
+		{
+			name:       "return without client",
+			query:      "?trx_id=96748&status=berhasil&status_code=1",
+			wantStatus: Success,
+		},
+		{
+			name:         "cancel defaults to canceled",
+			canceled:     true,
+			query:        "?trx_id=96748",
+			wantStatus:   Cancel,
+			wantCanceled: true,
+		},
+		{
+			name:         "cancel keeps reported status",
+			canceled:     true,
+			query:        "?trx_id=96748&status=pending",
+			wantStatus:   Pending,
+			wantCanceled: true,
+		},
+		{
+			name:        "forged status overridden by CheckTransaction",
+			client:      cl,
+			query:       "?trx_id=96748&sid=abc-123&reference_id=trx-123&status=berhasil&status_code=1",
+			wantStatus:  Pending,
+			wantChecked: true,
+		},
+		{
+			name:       "swapped trx_id",
+			client:     cl,
+			query:      "?trx_id=96748&sid=def-456&reference_id=trx-456&status=berhasil&status_code=1",
+			wantStatus: Success,
+			wantErr:    true,
+		},
+		{
+			name:       "swapped trx_id with only sid",
+			client:     cl,
+			query:      "?trx_id=96748&sid=def-456&status=berhasil&status_code=1",
+			wantStatus: Success,
+			wantErr:    true,
+		},
+		{
+			name:       "trx_id without reference_id or sid",
+			client:     cl,
+			query:      "?trx_id=96748&status=berhasil&status_code=1",
+			wantStatus: Success,
+			wantErr:    true,
+		},
+		{
+			name:       "missing trx_id with client",
+			client:     cl,
+			query:      "?status=berhasil",
+			wantStatus: Success,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *RedirectResult
+			var gotErr error
+			fn := func(w http.ResponseWriter, r *http.Request, result *RedirectResult, err error) {
+				got, gotErr = result, err
+			}
+			h := NewReturnHandler(fn)
+			if tt.canceled {
+				h = NewCancelHandler(fn)
+			}
+			h.Client = tt.client
+
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/return"+tt.query, nil))
+
+			if got == nil {
+				t.Fatal("ServeHTTP() did not call Func")
+			}
+			if (gotErr != nil) != tt.wantErr {
+				t.Errorf("ServeHTTP() err = %v, wantErr %v", gotErr, tt.wantErr)
+			}
+			if got.PaymentStatus != tt.wantStatus || got.Canceled != tt.wantCanceled || (got.Transaction != nil) != tt.wantChecked {
+				t.Errorf("ServeHTTP() result = %+v, want status %s canceled %v checked %v", got, tt.wantStatus, tt.wantCanceled, tt.wantChecked)
+			}
+		})
+	}
+}
+
+func TestRedirectHandler_NilFunc(t *testing.T) {
+	tests := []struct {
+		query    string
+		wantCode int
+	}{
+		{query: "?trx_id=96748&status=berhasil", wantCode: http.StatusOK},
+		{query: "?trx_id=abc", wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		NewReturnHandler(nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/return"+tt.query, nil))
+		if rec.Code != tt.wantCode {
+			t.Errorf("ServeHTTP(%s) code = %d, want %d", tt.query, rec.Code, tt.wantCode)
+		}
+	}
+}
@@ -1,16 +1,20 @@
 package ipaymu_go_api
 
+import "encoding/json"
+
 type RequestDirectMaster struct {
 	Name          *string       `json:"name"`
 	Phone         *string       `json:"phone"`
 	Email         *string       `json:"email"`
-	Amount        float64       `json:"amount"`
+	Amount        Money         `json:"amount"`
 	NotifyUrl     *string       `json:"notifyUrl"`
 	Expired       *int8         `json:"expired"`
 	ExpiredType   *ExpiredType  `json:"expiredType"`
 	Comments      *string       `json:"comments"`
 	ReferenceId   *string       `json:"referenceId"`
 	PaymentMethod PaymentMethod `json:"paymentMethod"`
+	Escrow        *bool         `json:"escrow,omitempty"`
+	Split         []SplitRule   `json:"split,omitempty"`
 }
 
 // AddBuyer sets the buyer's name, phone, and email for a direct payment request.
@@ -27,10 +31,28 @@ func (r *RequestDirectMaster) AddBuyer(name, phone, email string) {
     r.Email = &email
 }
 
+// EnableEscrow marks the direct payment as an escrow payment, holding the funds until ReleaseEscrow or RefundEscrow is called.
+func (r *RequestDirectMaster) EnableEscrow() {
+	escrow := true
+	r.Escrow = &escrow
+}
+
+// AddSplitFixed assigns a fixed amount of the payment to a member account.
+// The rules are validated against Amount when the payment is sent.
+func (r *RequestDirectMaster) AddSplitFixed(account string, amount Money) {
+	r.Split = append(r.Split, SplitRule{Account: account, Type: SplitFixed, Amount: amount})
+}
+
+// AddSplitPercent assigns a percentage of Amount to a member account.
+// The rules are validated against Amount when the payment is sent.
+func (r *RequestDirectMaster) AddSplitPercent(account string, percent float64) {
+	r.Split = append(r.Split, SplitRule{Account: account, Type: SplitPercent, Percent: percent})
+}
+
 type Product struct {
-	Product []string  `json:"product,omitempty"`
-	Qty     []int8    `json:"qty,omitempty"`
-	Price   []float64 `json:"price,omitempty"`
+	Product []string `json:"product,omitempty"`
+	Qty     []int8   `json:"qty,omitempty"`
+	Price   []Money  `json:"price,omitempty"`
 }
 
 type ProductWithCOD struct {
@@ -102,7 +124,7 @@ type RequestRedirect struct {
 	ReferenceId   *string        `json:"referenceId"`
 	Product       []string       `json:"product"`
 	Qty           []int8         `json:"qty"`
-	Price         []float64      `json:"price"`
+	Price         []Money        `json:"price"`
 	Weight        []float32      `json:"weight"`
 	Dimension     []string       `json:"dimension"`
 	BuyerName     *string        `json:"buyerName"`
@@ -111,24 +133,55 @@ type RequestRedirect struct {
 	PickupArea    *string        `json:"pickupArea"`
 	PickupAddress *string        `json:"pickupAddress"`
 	PaymentMethod *PaymentMethod `json:"paymentMethod"`
+	Escrow        *bool          `json:"escrow,omitempty"`
+	Split         []SplitRule    `json:"split,omitempty"`
 }
 
 func NewRequestRedirect() *RequestRedirect {
 	return &RequestRedirect{}
 }
 
+// EnableEscrow marks the redirect payment as an escrow payment, holding the funds until ReleaseEscrow or RefundEscrow is called.
+func (r *RequestRedirect) EnableEscrow() {
+	escrow := true
+	r.Escrow = &escrow
+}
+
+// AddSplitFixed assigns a fixed amount of the payment to a member account.
+// The rules are validated against TotalAmount when the payment is sent.
+func (r *RequestRedirect) AddSplitFixed(account string, amount Money) {
+	r.Split = append(r.Split, SplitRule{Account: account, Type: SplitFixed, Amount: amount})
+}
+
+// AddSplitPercent assigns a percentage of TotalAmount to a member account.
+// The rules are validated against TotalAmount when the payment is sent.
+func (r *RequestRedirect) AddSplitPercent(account string, percent float64) {
+	r.Split = append(r.Split, SplitRule{Account: account, Type: SplitPercent, Percent: percent})
+}
+
+// TotalAmount returns the amount of the redirect payment, the sum of price times quantity of every product.
+func (r *RequestRedirect) TotalAmount() Money {
+	var total Money
+	for i, price := range r.Price {
+		if i < len(r.Qty) {
+			total = total.Add(price.Mul(int64(r.Qty[i])))
+		}
+	}
+	return total
+}
+
 // AddProduct adds a product to the RequestRedirect struct.
 //
 // This function appends the provided product details to the respective fields of the RequestRedirect struct.
 // It accepts the following parameters:
 // - product: A string representing the name of the product.
 // - qty: An int8 representing the quantity of the product.
-// - price: A float64 representing the price of the product.
+// - price: A Money representing the price of the product.
 // - weight: A pointer to a float32 representing the weight of the product. If nil, it is ignored.
 // - dimension: A pointer to a string representing the dimension of the product. If nil, it is ignored.
 //
 // The function does not return any value. It modifies the RequestRedirect struct's Product, Qty, Price, Weight, and Dimension fields.
-func (r *RequestRedirect) AddProduct(product string, qty int8, price float64, weight *float32, dimension *string) {
+func (r *RequestRedirect) AddProduct(product string, qty int8, price Money, weight *float32, dimension *string) {
     r.Product = append(r.Product, product)
     r.Qty = append(r.Qty, qty)
     r.Price = append(r.Price, price)
@@ -146,7 +199,7 @@ type RequestTransactionHistory struct {
 	Date       *FilterDate       `json:"date"`
 	StartDate  *string           `json:"startdate"` // Format Y-m-d
 	EndDate    *string           `json:"enddate"`   // Format Y-m-d
-	Page       *int8             `json:"page"`
+	Page       *int              `json:"page"`
 	OrderBy    *FilterOrderBy    `json:"orderBy"`
 	Order      *FilterOrder      `json:"order"`
 	Limit      *int8             `json:"limit"` // Max Limit 20
@@ -160,10 +213,105 @@ func NewRequestTransactionHistory() *RequestTransactionHistory {
 	return &RequestTransactionHistory{}
 }
 
+// RequestCallBack is the payload iPaymu posts to the notifyUrl of a payment.
+//
+// Numbers may be sent as strings and are decoded either way. Fields not known to this
+// struct are kept in Extra.
 type RequestCallBack struct {
-	TrxID       int    `json:"trx_id"`
-	Status      string `json:"status"`
-	StatusCode  int8   `json:"status_code"`
-	SID         string `json:"sid"`
-	ReferenceID string `json:"reference_id"`
+	TrxID                 int             `json:"trx_id"`
+	SID                   string          `json:"sid"`
+	ReferenceID           string          `json:"reference_id"`
+	Status                string          `json:"status"`
+	StatusCode            PaymentStatus   `json:"status_code"`
+	SubTotal              Money           `json:"sub_total"`
+	Total                 Money           `json:"total"`
+	Amount                Money           `json:"amount"`
+	Fee                   Money           `json:"fee"`
+	PaidOff               Money           `json:"paid_off"`
+	CreatedAt             NullTime        `json:"created_at"`
+	ExpiredAt             NullTime        `json:"expired_at"`
+	PaidAt                NullTime        `json:"paid_at"`
+	SettlementStatus      string          `json:"settlement_status"`
+	TransactionStatusCode int             `json:"transaction_status_code"`
+	IsEscrow              bool            `json:"is_escrow"`
+	SystemNotes           string          `json:"system_notes"`
+	Via                   string          `json:"via"`
+	Channel               string          `json:"channel"`
+	PaymentNo             string          `json:"payment_no"`
+	BuyerName             string          `json:"buyer_name"`
+	BuyerEmail            string          `json:"buyer_email"`
+	BuyerPhone            string          `json:"buyer_phone"`
+	AdditionalInfo        json.RawMessage `json:"additional_info,omitempty"`
+	Url                   string          `json:"url"`
+	Va                    string          `json:"va"`
+
+	// Extra holds the fields of the payload that are not mapped above.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type RequestBankInquiry struct {
+	BankCode      string `json:"bankCode"`
+	AccountNumber string `json:"accountNumber"`
+}
+
+type RequestWithdraw struct {
+	Amount        Money   `json:"amount"`
+	BankCode      string  `json:"bankCode"`
+	AccountNumber string  `json:"accountNumber"`
+	AccountName   *string `json:"accountName"`
+	Notes         *string `json:"notes"`
+	ReferenceId   *string `json:"referenceId"`
+}
+
+// NewRequestWithdraw creates a new instance of RequestWithdraw for the given amount and destination account.
+//
+// Parameters:
+// - amount: The amount to withdraw from the merchant balance.
+// - bankCode: The code of the destination bank, as returned by ListBank.
+// - accountNumber: The destination bank account number.
+//
+// Return:
+// - A pointer to a new RequestWithdraw instance.
+func NewRequestWithdraw(amount Money, bankCode, accountNumber string) *RequestWithdraw {
+	return &RequestWithdraw{
+		Amount:        amount,
+		BankCode:      bankCode,
+		AccountNumber: accountNumber,
+	}
+}
+
+type RequestTransferBalance struct {
+	Sender      string  `json:"sender"`
+	Receiver    string  `json:"receiver"`
+	Amount      Money   `json:"amount"`
+	Notes       *string `json:"notes"`
+	ReferenceId *string `json:"referenceId"`
+}
+
+// NewRequestTransferBalance creates a new instance of RequestTransferBalance that sends amount to the receiver account.
+//
+// The sender is left empty so that TransferBalance uses the client's virtual account; set Sender to move
+// funds out of a member account instead.
+func NewRequestTransferBalance(receiver string, amount Money) *RequestTransferBalance {
+	return &RequestTransferBalance{
+		Receiver: receiver,
+		Amount:   amount,
+	}
+}
+
+type RequestRegisterMember struct {
+	Name     string `json:"name"`
+	Phone    string `json:"phone"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// NewRequestRegisterMember creates a new instance of RequestRegisterMember with the member's identity and password.
+func NewRequestRegisterMember(name, phone, email, password string) *RequestRegisterMember {
+	return &RequestRegisterMember{
+		Name:     name,
+		Phone:    phone,
+		Email:    email,
+		Password: password,
+	}
 }
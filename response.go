@@ -1,134 +1,296 @@
 package ipaymu_go_api
 
-type Response struct {
-	Status int64
+// Envelope is the Status, Message and Data shape shared by every iPaymu response.
+// The response types are defined on it, so a response can be converted to its envelope,
+// as in Envelope[ResponseCheckData](res), to handle different endpoints the same way.
+type Envelope[T any] struct {
+	responseMeta
 
-	// v2 payment
-	Message string        `json:"Message,omitempty"`
-	Data    *ResponseData `json:"Data,omitempty"`
+	Status  FlexInt `json:"Status"`
+	Message string  `json:"Message"`
+	Data    T       `json:"Data"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the raw body and the unknown fields.
+func (e *Envelope[T]) UnmarshalJSON(data []byte) error {
+	var v struct {
+		Status  FlexInt `json:"Status"`
+		Message string  `json:"Message"`
+		Data    T       `json:"Data"`
+	}
+	if err := e.responseMeta.decode(data, &v); err != nil {
+		return err
+	}
+	e.Status, e.Message, e.Data = v.Status, v.Message, v.Data
+	return nil
+}
+
+// Response is returned by the payment endpoints.
+type Response Envelope[*ResponseData]
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the raw body and the unknown fields.
+func (r *Response) UnmarshalJSON(data []byte) error {
+	type response Response
+	return r.responseMeta.decode(data, (*response)(r))
 }
 
 type ResponseData struct {
-	SessionId     string  `json:"SessionId,omitempty"`
-	TransactionId int64   `json:"TransactionId,omitempty"`
-	ReferenceId   string  `json:"ReferenceId,omitempty"`
-	Via           string  `json:"Via,omitempty"`
-	Channel       string  `json:"Channel,omitempty"`
-	PaymentNo     string  `json:"PaymentNo,omitempty"`
-	PaymentName   string  `json:"PaymentName,omitempty"`
-	Total         float64 `json:"Total,omitempty"`
-	Fee           int64   `json:"Fee,omitempty"`
-	Expired       string  `json:"Expired,omitempty"`
-	Note          string  `json:"Note,omitempty"`
-	Url           string  `json:"Url,omitempty"`
-}
-
-type ResponseCheck struct {
-	Status int `json:"Status"`
-	Data   struct {
-		TransactionId  int         `json:"TransactionId"`
-		SessionId      string      `json:"SessionId"`
-		ReferenceId    interface{} `json:"ReferenceId"`
-		RelatedId      int         `json:"RelatedId"`
-		Sender         string      `json:"Sender"`
-		Receiver       string      `json:"Receiver"`
-		Amount         int         `json:"Amount"`
-		Fee            int         `json:"Fee"`
-		Status         int         `json:"Status"`
-		StatusDesc     string      `json:"StatusDesc"`
-		Type           int         `json:"Type"`
-		TypeDesc       string      `json:"TypeDesc"`
-		Notes          string      `json:"Notes"`
-		CreatedDate    string      `json:"CreatedDate"`
-		ExpiredDate    string      `json:"ExpiredDate"`
-		SuccessDate    string      `json:"SuccessDate"`
-		SettlementDate string      `json:"SettlementDate"`
-	} `json:"Data"`
-	Message string `json:"Message"`
-}
-
-type ResponseBalance struct {
-	Status int `json:"Status"`
-	Data   struct {
-		Va              string  `json:"Va"`
-		MerchantBalance float64 `json:"MerchantBalance"`
-		MemberBalance   float64 `json:"MemberBalance"`
-	} `json:"Data"`
-	Message string `json:"Message"`
+	SessionId     FlexString `json:"SessionId,omitempty"`
+	TransactionId FlexInt    `json:"TransactionId,omitempty"`
+	ReferenceId   FlexString `json:"ReferenceId,omitempty"`
+	Via           string     `json:"Via,omitempty"`
+	Channel       string     `json:"Channel,omitempty"`
+	PaymentNo     FlexString `json:"PaymentNo,omitempty"`
+	PaymentName   string     `json:"PaymentName,omitempty"`
+	Total         Money      `json:"Total,omitempty"`
+	Fee           Money      `json:"Fee,omitempty"`
+	Expired       string     `json:"Expired,omitempty"`
+	Note          string     `json:"Note,omitempty"`
+	Url           string     `json:"Url,omitempty"`
+}
+
+type ResponseCheck Envelope[ResponseCheckData]
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the raw body and the unknown fields.
+func (r *ResponseCheck) UnmarshalJSON(data []byte) error {
+	type response ResponseCheck
+	return r.responseMeta.decode(data, (*response)(r))
+}
+
+// ResponseCheckData is the transaction returned by CheckTransaction.
+type ResponseCheckData struct {
+	TransactionId  FlexInt       `json:"TransactionId"`
+	SessionId      FlexString    `json:"SessionId"`
+	ReferenceId    FlexString    `json:"ReferenceId"`
+	RelatedId      FlexInt       `json:"RelatedId"`
+	Sender         FlexString    `json:"Sender"`
+	Receiver       FlexString    `json:"Receiver"`
+	Amount         Money         `json:"Amount"`
+	Fee            Money         `json:"Fee"`
+	Status         PaymentStatus `json:"Status"`
+	StatusDesc     string        `json:"StatusDesc"`
+	Type           FlexInt       `json:"Type"`
+	TypeDesc       string        `json:"TypeDesc"`
+	Notes          FlexString    `json:"Notes"`
+	CreatedDate    NullTime      `json:"CreatedDate"`
+	ExpiredDate    NullTime      `json:"ExpiredDate"`
+	SuccessDate    NullTime      `json:"SuccessDate"`
+	SettlementDate NullTime      `json:"SettlementDate"`
+}
+
+type ResponseBalance Envelope[ResponseBalanceData]
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the raw body and the unknown fields.
+func (r *ResponseBalance) UnmarshalJSON(data []byte) error {
+	type response ResponseBalance
+	return r.responseMeta.decode(data, (*response)(r))
+}
+
+// ResponseBalanceData holds the balances of a merchant or member account.
+type ResponseBalanceData struct {
+	Va              FlexString `json:"Va"`
+	MerchantBalance Money      `json:"MerchantBalance"`
+	MemberBalance   Money      `json:"MemberBalance"`
 }
 
+// ResponseTransaction is returned by the history endpoint. Unlike the other responses it also
+// carries a Success flag, so it is not defined on Envelope.
 type ResponseTransaction struct {
-	Status  int    `json:"Status"`
-	Success bool   `json:"Success"`
-	Message string `json:"Message"`
-	Data    struct {
-		Transaction []Transaction `json:"Transaction"`
-		Pagination  struct {
-			Total       int `json:"total"`
-			Count       int `json:"count"`
-			PerPage     int `json:"per_page"`
-			CurrentPage int `json:"current_page"`
-			TotalPages  int `json:"total_pages"`
-		} `json:"Pagination"`
-	} `json:"Data"`
+	responseMeta
+
+	Status  FlexInt                 `json:"Status"`
+	Success FlexBool                `json:"Success"`
+	Message string                  `json:"Message"`
+	Data    ResponseTransactionData `json:"Data"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the raw body and the unknown fields.
+func (r *ResponseTransaction) UnmarshalJSON(data []byte) error {
+	type response ResponseTransaction
+	return r.responseMeta.decode(data, (*response)(r))
+}
+
+// ResponseTransactionData holds one page of the transaction history.
+type ResponseTransactionData struct {
+	Transaction []Transaction `json:"Transaction"`
+	Pagination  Pagination    `json:"Pagination"`
+}
+
+// Pagination describes the page of a paginated response.
+type Pagination struct {
+	Total       FlexInt `json:"total"`
+	Count       FlexInt `json:"count"`
+	PerPage     FlexInt `json:"per_page"`
+	CurrentPage FlexInt `json:"current_page"`
+	TotalPages  FlexInt `json:"total_pages"`
 }
 
 type Transaction struct {
-	TransactionId  int         `json:"TransactionId"`
-	SessionId      *string     `json:"SessionId"`
-	ReferenceId    *string     `json:"ReferenceId"`
-	RelatedId      int         `json:"RelatedId"`
-	Sender         string      `json:"Sender"`
-	Receiver       string      `json:"Receiver"`
-	Amount         int         `json:"Amount"`
-	Fee            int         `json:"Fee"`
-	Status         int         `json:"Status"`
-	StatusDesc     string      `json:"StatusDesc"`
-	PaidStatus     string      `json:"PaidStatus"`
-	Type           int         `json:"Type"`
-	TypeDesc       string      `json:"TypeDesc"`
-	Notes          *string     `json:"Notes"`
-	IsEscrow       bool        `json:"IsEscrow"`
-	CreatedDate    string      `json:"CreatedDate"`
-	ExpiredDate    string      `json:"ExpiredDate"`
-	SuccessDate    interface{} `json:"SuccessDate"`
-	SettlementDate interface{} `json:"SettlementDate"`
-	PaymentChannel string      `json:"PaymentChannel"`
-	PaymentCode    string      `json:"PaymentCode"`
-	BuyerName      string      `json:"BuyerName"`
-	BuyerPhone     string      `json:"BuyerPhone"`
-	BuyerEmail     string      `json:"BuyerEmail"`
-}
-
-type ResponseListPayment struct {
-	Status int `json:"Status"`
-	Data   []struct {
-		Code          string                 `json:"Code"`
-		Description   string                 `json:"Description"`
-		Channels      []PaymentChannelDetail `json:"Channels,omitempty"`
-		PaymentMethod []PaymentMethodDetail  `json:"PaymentMethod,omitempty"`
-	} `json:"Data"`
-	Message string `json:"Message"`
+	TransactionId  FlexInt       `json:"TransactionId"`
+	SessionId      FlexString    `json:"SessionId"`
+	ReferenceId    FlexString    `json:"ReferenceId"`
+	RelatedId      FlexInt       `json:"RelatedId"`
+	Sender         FlexString    `json:"Sender"`
+	Receiver       FlexString    `json:"Receiver"`
+	Amount         Money         `json:"Amount"`
+	Fee            Money         `json:"Fee"`
+	Status         PaymentStatus `json:"Status"`
+	StatusDesc     string        `json:"StatusDesc"`
+	PaidStatus     string        `json:"PaidStatus"`
+	Type           FlexInt       `json:"Type"`
+	TypeDesc       string        `json:"TypeDesc"`
+	Notes          FlexString    `json:"Notes"`
+	IsEscrow       FlexBool      `json:"IsEscrow"`
+	CreatedDate    NullTime      `json:"CreatedDate"`
+	ExpiredDate    NullTime      `json:"ExpiredDate"`
+	SuccessDate    NullTime      `json:"SuccessDate"`
+	SettlementDate NullTime      `json:"SettlementDate"`
+	PaymentChannel string        `json:"PaymentChannel"`
+	PaymentCode    FlexString    `json:"PaymentCode"`
+	BuyerName      string        `json:"BuyerName"`
+	BuyerPhone     FlexString    `json:"BuyerPhone"`
+	BuyerEmail     string        `json:"BuyerEmail"`
+}
+
+type ResponseListPayment Envelope[[]PaymentMethodGroup]
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the raw body and the unknown fields.
+func (r *ResponseListPayment) UnmarshalJSON(data []byte) error {
+	type response ResponseListPayment
+	return r.responseMeta.decode(data, (*response)(r))
+}
+
+// PaymentMethodGroup is a payment method, such as "va" or "cstore", with its channels.
+type PaymentMethodGroup struct {
+	Code          string                 `json:"Code"`
+	Description   string                 `json:"Description"`
+	Channels      []PaymentChannelDetail `json:"Channels,omitempty"`
+	PaymentMethod []PaymentMethodDetail  `json:"PaymentMethod,omitempty"`
 }
 
 type PaymentChannelDetail struct {
-	Code                 string `json:"Code"`
-	Description          string `json:"Description"`
-	PaymentIntrucionsDoc string `json:"PaymentIntrucionsDoc"`
-	TransactionFee       struct {
-		ActualFee     float64 `json:"ActualFee"`
-		ActualFeeType string  `json:"ActualFeeType"`
-		AdditionalFee float64 `json:"AdditionalFee"`
-	} `json:"TransactionFee"`
+	Code                 string         `json:"Code"`
+	Description          string         `json:"Description"`
+	PaymentIntrucionsDoc string         `json:"PaymentIntrucionsDoc"`
+	TransactionFee       TransactionFee `json:"TransactionFee"`
 }
 
 type PaymentMethodDetail struct {
-	Code                 string  `json:"Code"`
-	Description          string  `json:"Description"`
-	PaymentIntrucionsDoc *string `json:"PaymentIntrucionsDoc"`
-	TransactionFee       struct {
-		ActualFee     float64 `json:"ActualFee"`
-		ActualFeeType string  `json:"ActualFeeType"`
-		AdditionalFee float64 `json:"AdditionalFee"`
-	} `json:"TransactionFee"`
+	Code                 string         `json:"Code"`
+	Description          string         `json:"Description"`
+	PaymentIntrucionsDoc *string        `json:"PaymentIntrucionsDoc"`
+	TransactionFee       TransactionFee `json:"TransactionFee"`
+}
+
+// TransactionFee is the fee charged for a payment channel. ActualFee is an amount or a
+// percentage depending on ActualFeeType.
+type TransactionFee struct {
+	ActualFee     float64 `json:"ActualFee"`
+	ActualFeeType string  `json:"ActualFeeType"`
+	AdditionalFee Money   `json:"AdditionalFee"`
+}
+
+type ResponseBankList Envelope[[]Bank]
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the raw body and the unknown fields.
+func (r *ResponseBankList) UnmarshalJSON(data []byte) error {
+	type response ResponseBankList
+	return r.responseMeta.decode(data, (*response)(r))
+}
+
+type Bank struct {
+	Code string `json:"Code"`
+	Name string `json:"Name"`
+}
+
+type ResponseBankInquiry Envelope[ResponseBankInquiryData]
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the raw body and the unknown fields.
+func (r *ResponseBankInquiry) UnmarshalJSON(data []byte) error {
+	type response ResponseBankInquiry
+	return r.responseMeta.decode(data, (*response)(r))
+}
+
+// ResponseBankInquiryData is the bank account returned by CheckBankAccount.
+type ResponseBankInquiryData struct {
+	BankCode      string     `json:"BankCode"`
+	BankName      string     `json:"BankName"`
+	AccountNumber FlexString `json:"AccountNumber"`
+	AccountName   string     `json:"AccountName"`
+}
+
+type ResponseWithdraw Envelope[ResponseWithdrawData]
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the raw body and the unknown fields.
+func (r *ResponseWithdraw) UnmarshalJSON(data []byte) error {
+	type response ResponseWithdraw
+	return r.responseMeta.decode(data, (*response)(r))
+}
+
+// ResponseWithdrawData is the withdrawal returned by Withdraw and CheckWithdraw.
+type ResponseWithdrawData struct {
+	WithdrawId    FlexInt    `json:"WithdrawId"`
+	ReferenceId   FlexString `json:"ReferenceId"`
+	BankCode      string     `json:"BankCode"`
+	AccountNumber FlexString `json:"AccountNumber"`
+	AccountName   string     `json:"AccountName"`
+	Amount        Money      `json:"Amount"`
+	Fee           Money      `json:"Fee"`
+	Status        FlexInt    `json:"Status"`
+	StatusDesc    string     `json:"StatusDesc"`
+	CreatedDate   NullTime   `json:"CreatedDate"`
+	SuccessDate   NullTime   `json:"SuccessDate"`
+}
+
+type ResponseTransfer Envelope[ResponseTransferData]
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the raw body and the unknown fields.
+func (r *ResponseTransfer) UnmarshalJSON(data []byte) error {
+	type response ResponseTransfer
+	return r.responseMeta.decode(data, (*response)(r))
+}
+
+// ResponseTransferData is the transfer returned by TransferBalance.
+type ResponseTransferData struct {
+	TransactionId FlexInt    `json:"TransactionId"`
+	ReferenceId   FlexString `json:"ReferenceId"`
+	Sender        FlexString `json:"Sender"`
+	Receiver      FlexString `json:"Receiver"`
+	Amount        Money      `json:"Amount"`
+	Fee           Money      `json:"Fee"`
+	Notes         FlexString `json:"Notes"`
+}
+
+type ResponseMember Envelope[ResponseMemberData]
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the raw body and the unknown fields.
+func (r *ResponseMember) UnmarshalJSON(data []byte) error {
+	type response ResponseMember
+	return r.responseMeta.decode(data, (*response)(r))
+}
+
+// ResponseMemberData is the member account returned by RegisterMember and GetMember.
+type ResponseMemberData struct {
+	Va          FlexString `json:"Va"`
+	Name        string     `json:"Name"`
+	Email       string     `json:"Email"`
+	Phone       string     `json:"Phone"`
+	Status      string     `json:"Status"`
+	CreatedDate NullTime   `json:"CreatedDate"`
+}
+
+type ResponseEscrow Envelope[ResponseEscrowData]
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the raw body and the unknown fields.
+func (r *ResponseEscrow) UnmarshalJSON(data []byte) error {
+	type response ResponseEscrow
+	return r.responseMeta.decode(data, (*response)(r))
+}
+
+// ResponseEscrowData is the escrow transaction returned by ReleaseEscrow and RefundEscrow.
+type ResponseEscrowData struct {
+	TransactionId FlexInt       `json:"TransactionId"`
+	ReferenceId   FlexString    `json:"ReferenceId"`
+	Status        PaymentStatus `json:"Status"`
+	StatusDesc    string        `json:"StatusDesc"`
 }
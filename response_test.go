@@ -0,0 +1,182 @@
+package ipaymu_go_api
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// responseFixtures maps each directory under testdata/responses to the response type its payloads decode into.
+var responseFixtures = map[string]func() interface{}{
+	"check":       func() interface{} { return new(ResponseCheck) },
+	"transaction": func() interface{} { return new(ResponseTransaction) },
+	"payment":     func() interface{} { return new(Response) },
+	"balance":     func() interface{} { return new(ResponseBalance) },
+}
+
+func TestResponseFixtures(t *testing.T) {
+	for dir, newResponse := range responseFixtures {
+		files, err := filepath.Glob(filepath.Join("testdata", "responses", dir, "*.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) == 0 {
+			t.Errorf("no fixtures in testdata/responses/%s", dir)
+		}
+		for _, file := range files {
+			t.Run(dir+"/"+filepath.Base(file), func(t *testing.T) {
+				data, err := os.ReadFile(file)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := json.Unmarshal(data, newResponse()); err != nil {
+					t.Errorf("Unmarshal() error = %v", err)
+				}
+			})
+		}
+	}
+}
+
+func readFixture(t *testing.T, name string, v interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "responses", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("Unmarshal(%s) error = %v", name, err)
+	}
+}
+
+func TestResponseCheck_Variants(t *testing.T) {
+	tests := []struct {
+		fixture       string
+		wantID        int
+		wantReference string
+		wantSender    string
+		wantAmount    Money
+		wantStatus    PaymentStatus
+		wantExpired   bool
+	}{
+		{
+			fixture:       "check/production.json",
+			wantID:        96748,
+			wantReference: "INV-2024-0001",
+			wantSender:    "Budi",
+			wantAmount:    100000,
+			wantStatus:    Success,
+			wantExpired:   true,
+		},
+		{
+			fixture:       "check/sandbox_numeric_reference.json",
+			wantID:        96748,
+			wantReference: "20240001",
+			wantSender:    "6281234567890",
+			wantAmount:    100000,
+			wantStatus:    Success,
+			wantExpired:   false,
+		},
+		{
+			fixture:       "check/null_reference.json",
+			wantID:        96749,
+			wantReference: "",
+			wantSender:    "",
+			wantAmount:    50000,
+			wantStatus:    Pending,
+			wantExpired:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			var res ResponseCheck
+			readFixture(t, tt.fixture, &res)
+
+			if res.Status != 200 {
+				t.Errorf("Status = %d, want 200", res.Status)
+			}
+			data := res.Data
+			if data.TransactionId.Int() != tt.wantID || data.ReferenceId.String() != tt.wantReference || data.Sender.String() != tt.wantSender {
+				t.Errorf("Data = %d %q %q, want %d %q %q", data.TransactionId, data.ReferenceId, data.Sender, tt.wantID, tt.wantReference, tt.wantSender)
+			}
+			if data.Amount != tt.wantAmount || data.Status != tt.wantStatus {
+				t.Errorf("Data amount, status = %s %s, want %s %s", data.Amount, data.Status, tt.wantAmount, tt.wantStatus)
+			}
+			if data.ExpiredDate.Valid != tt.wantExpired {
+				t.Errorf("ExpiredDate = %+v, want valid %v", data.ExpiredDate, tt.wantExpired)
+			}
+		})
+	}
+}
+
+func TestResponseTransaction_Variants(t *testing.T) {
+	var production, sandbox ResponseTransaction
+	readFixture(t, "transaction/production.json", &production)
+	readFixture(t, "transaction/sandbox_mixed_types.json", &sandbox)
+
+	if !sandbox.Success || sandbox.Data.Pagination.TotalPages != 1 || len(sandbox.Data.Transaction) != 1 {
+		t.Fatalf("sandbox = %+v, want one successful page", sandbox)
+	}
+
+	want, got := production.Data.Transaction[0], sandbox.Data.Transaction[0]
+	if got.TransactionId != want.TransactionId || got.Amount != want.Amount || got.Fee != want.Fee || got.Type != want.Type {
+		t.Errorf("sandbox transaction = %+v, want %+v", got, want)
+	}
+	if got.ReferenceId != "20240001" || got.Notes != "12345" || got.PaymentCode != "8277011234567890" {
+		t.Errorf("sandbox strings = %q %q %q", got.ReferenceId, got.Notes, got.PaymentCode)
+	}
+	if got.IsEscrow || got.SessionId != "" {
+		t.Errorf("sandbox IsEscrow, SessionId = %v %q, want false \"\"", got.IsEscrow, got.SessionId)
+	}
+	if !got.SuccessDate.Time.Equal(want.SuccessDate.Time) || got.SettlementDate.Valid {
+		t.Errorf("sandbox dates = %v %+v", got.SuccessDate, got.SettlementDate)
+	}
+}
+
+func TestFlexInt_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		data    string
+		want    FlexInt
+		wantErr bool
+	}{
+		{data: `42`, want: 42},
+		{data: `"42"`, want: 42},
+		{data: `42.0`, want: 42},
+		{data: `null`, want: 0},
+		{data: `""`, want: 0},
+		{data: `42.5`, wantErr: true},
+		{data: `"abc"`, wantErr: true},
+		{data: `[1]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			var got FlexInt
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvelope_UnmarshalJSON(t *testing.T) {
+	var env Envelope[ResponseBalanceData]
+	readFixture(t, "balance/sandbox_numeric_va.json", &env)
+
+	want := ResponseBalanceData{Va: "1179000899", MerchantBalance: 1500000}
+	if env.Status != 200 || env.Message != "success" || env.Data != want {
+		t.Errorf("Envelope = %+v, want status 200 and %+v", env, want)
+	}
+	if len(env.Raw()) == 0 || len(env.UnknownFields()) != 0 {
+		t.Errorf("Envelope Raw, UnknownFields = %s %v", env.Raw(), env.UnknownFields())
+	}
+
+	var res ResponseBalance
+	readFixture(t, "balance/sandbox_numeric_va.json", &res)
+	if got := Envelope[ResponseBalanceData](res); got.Data != env.Data || got.Status != env.Status {
+		t.Errorf("Envelope(ResponseBalance) = %+v, want %+v", got, env)
+	}
+}
@@ -0,0 +1,216 @@
+package ipaymu_go_api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// responseMeta is embedded in every response type. It keeps the JSON the response was
+// decoded from and the fields that were not mapped to the struct, so that fields added by
+// iPaymu are not lost before the SDK knows about them.
+type responseMeta struct {
+	raw     json.RawMessage
+	unknown map[string]json.RawMessage
+	missing []string
+}
+
+// Raw returns the JSON body the response was decoded from.
+func (m responseMeta) Raw() json.RawMessage {
+	return m.raw
+}
+
+// UnknownFields returns the fields in the body that the response type does not map, keyed by
+// their path such as "Data.NewField". Fields of list elements use "[]" in the path, as in
+// "Data.Transaction[].NewField".
+func (m responseMeta) UnknownFields() map[string]json.RawMessage {
+	return m.unknown
+}
+
+// MissingFields returns the paths of fields the response type maps that were absent from the body.
+// Fields tagged omitempty and fields under a null object are not reported.
+func (m responseMeta) MissingFields() []string {
+	return m.missing
+}
+
+// decode unmarshals data into v and records the raw body and the unknown and missing fields.
+// v must be a pointer to a type without an UnmarshalJSON method, usually a local alias of the response type.
+func (m *responseMeta) decode(data []byte, v interface{}) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	m.raw = append(json.RawMessage(nil), data...)
+	m.unknown = make(map[string]json.RawMessage)
+	missing := make(map[string]bool)
+	walkSchema(data, reflect.TypeOf(v).Elem(), "", m.unknown, missing)
+
+	m.missing = nil
+	for path := range missing {
+		m.missing = append(m.missing, path)
+	}
+	sort.Strings(m.missing)
+	return nil
+}
+
+// SchemaDrift describes the differences between a response body and the type it was decoded into.
+type SchemaDrift struct {
+	// Endpoint is the URL path of the request, such as "/api/v2/transaction".
+	Endpoint string
+	// Type is the name of the response type, such as "ResponseCheck".
+	Type    string
+	Unknown []string
+	Missing []string
+}
+
+// schemaResponse is implemented by every response type through responseMeta.
+type schemaResponse interface {
+	UnknownFields() map[string]json.RawMessage
+	MissingFields() []string
+}
+
+// decodeResponse unmarshals an API response into res and, when OnSchemaDrift is set,
+// reports fields that were unknown or missing.
+func (c *Client) decodeResponse(uri *url.URL, data []byte, res interface{}) error {
+	if err := json.Unmarshal(data, res); err != nil {
+		return err
+	}
+	if c.OnSchemaDrift == nil {
+		return nil
+	}
+
+	r, ok := res.(schemaResponse)
+	if !ok {
+		return nil
+	}
+	drift := SchemaDrift{
+		Endpoint: uri.Path,
+		Type:     reflect.TypeOf(res).Elem().Name(),
+		Missing:  r.MissingFields(),
+	}
+	for path := range r.UnknownFields() {
+		drift.Unknown = append(drift.Unknown, path)
+	}
+	sort.Strings(drift.Unknown)
+
+	if len(drift.Unknown) > 0 || len(drift.Missing) > 0 {
+		c.OnSchemaDrift(drift)
+	}
+	return nil
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// walkSchema compares the JSON value data with the type t, adding the paths of object keys
+// t does not map to unknown and of fields t maps but data lacks to missing.
+func walkSchema(data json.RawMessage, t reflect.Type, path string, unknown map[string]json.RawMessage, missing map[string]bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return
+	}
+	// Types with their own decoding, such as Money or NullTime, are treated as a single value.
+	if reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		var object map[string]json.RawMessage
+		if json.Unmarshal(data, &object) != nil {
+			return
+		}
+		fields := schemaFields(t)
+		seen := make(map[int]bool)
+		for key, value := range object {
+			i := matchSchemaField(fields, key)
+			if i < 0 {
+				unknown[joinSchemaPath(path, key)] = value
+				continue
+			}
+			seen[i] = true
+			walkSchema(value, fields[i].typ, joinSchemaPath(path, fields[i].name), unknown, missing)
+		}
+		for i, field := range fields {
+			if !seen[i] && !field.omitEmpty {
+				missing[joinSchemaPath(path, field.name)] = true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		var list []json.RawMessage
+		if json.Unmarshal(data, &list) != nil {
+			return
+		}
+		for _, item := range list {
+			walkSchema(item, t.Elem(), path+"[]", unknown, missing)
+		}
+	}
+}
+
+type schemaField struct {
+	name      string
+	typ       reflect.Type
+	omitEmpty bool
+}
+
+// schemaFields lists the JSON fields of the struct type t, including those of embedded structs.
+func schemaFields(t reflect.Type) []schemaField {
+	var fields []schemaField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				fields = append(fields, schemaFields(ft)...)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, schemaField{
+			name:      name,
+			typ:       f.Type,
+			omitEmpty: strings.Contains(","+opts+",", ",omitempty,"),
+		})
+	}
+	return fields
+}
+
+// matchSchemaField finds the field for key the way encoding/json does, preferring an exact
+// match over a case-insensitive one. It returns -1 when no field matches.
+func matchSchemaField(fields []schemaField, key string) int {
+	match := -1
+	for i, field := range fields {
+		if field.name == key {
+			return i
+		}
+		if match < 0 && strings.EqualFold(field.name, key) {
+			match = i
+		}
+	}
+	return match
+}
+
+func joinSchemaPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
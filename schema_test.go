@@ -0,0 +1,95 @@
+package ipaymu_go_api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestResponse_UnknownFields(t *testing.T) {
+	var res ResponseTransaction
+	readFixture(t, "transaction/new_fields.json", &res)
+
+	data, err := os.ReadFile(filepath.Join("testdata", "responses", "transaction", "new_fields.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(res.Raw(), bytes.TrimSpace(data)) {
+		t.Errorf("Raw() = %s, want the fixture body", res.Raw())
+	}
+
+	unknown := res.UnknownFields()
+	want := map[string]string{
+		"RequestId":                      `"a1b2c3"`,
+		"Data.Summary":                   `{"Amount":100000}`,
+		"Data.Transaction[].Installment": `{"Tenor":3}`,
+	}
+	if len(unknown) != len(want) {
+		t.Errorf("UnknownFields() = %v, want %d fields", unknown, len(want))
+	}
+	for path, value := range want {
+		if string(unknown[path]) != value {
+			t.Errorf("UnknownFields()[%q] = %s, want %s", path, unknown[path], value)
+		}
+	}
+
+	if got := res.MissingFields(); !reflect.DeepEqual(got, []string{"Data.Transaction[].BuyerPhone"}) {
+		t.Errorf("MissingFields() = %v, want [Data.Transaction[].BuyerPhone]", got)
+	}
+}
+
+func TestClient_OnSchemaDrift(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantDrift *SchemaDrift
+	}{
+		{
+			name:      "matching body",
+			body:      `{"Status":200,"Data":{"Va":"1179000899","MerchantBalance":50000,"MemberBalance":0},"Message":"success"}`,
+			wantDrift: nil,
+		},
+		{
+			name: "unknown and missing fields",
+			body: `{"Status":200,"Data":{"Va":"1179000899","MerchantBalance":50000,"HoldBalance":1000},"Message":"success"}`,
+			wantDrift: &SchemaDrift{
+				Endpoint: "/api/v2/balance",
+				Type:     "ResponseBalance",
+				Unknown:  []string{"Data.HoldBalance"},
+				Missing:  []string{"Data.MemberBalance"},
+			},
+		},
+		{
+			name:      "null data on error",
+			body:      `{"Status":401,"Data":null,"Message":"unauthorized"}`,
+			wantDrift: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			var got *SchemaDrift
+			cl := &Client{}
+			cl.AssignCredential("api-key", "1179000899", EnvironmentType(srv.URL))
+			cl.OnSchemaDrift = func(drift SchemaDrift) {
+				got = &drift
+			}
+
+			res, _ := cl.GetBalance()
+			if !reflect.DeepEqual(got, tt.wantDrift) {
+				t.Errorf("OnSchemaDrift() got %+v, want %+v", got, tt.wantDrift)
+			}
+			if !bytes.Equal(res.Raw(), []byte(tt.body)) {
+				t.Errorf("Raw() = %s, want %s", res.Raw(), tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package ipaymu_go_api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type CallbackFormat string
+
+const (
+	FormCallback CallbackFormat = "form"
+	JSONCallback CallbackFormat = "json"
+)
+
+// simulatedStatusText is the status text iPaymu sends along with each status code.
+var simulatedStatusText = map[PaymentStatus]string{
+	Expired:         "expired",
+	Pending:         "pending",
+	Success:         "berhasil",
+	Cancel:          "batal",
+	Refund:          "refund",
+	Error:           "error",
+	Failed:          "gagal",
+	SuccessUnsettle: "berhasil",
+	Escrow:          "escrow",
+}
+
+// NewSimulatedCallback builds a callback like the one iPaymu posts for a transaction in the given status.
+//
+// The amount, fee, channel, and buyer fields are filled with sandbox-like values; adjust them on the
+// returned callback when the handler under test depends on them.
+func NewSimulatedCallback(trxID int, referenceID string, status PaymentStatus) RequestCallBack {
+	now := time.Now().In(jakarta).Truncate(time.Second)
+	cb := RequestCallBack{
+		TrxID:                 trxID,
+		SID:                   fmt.Sprintf("sim-%d", trxID),
+		ReferenceID:           referenceID,
+		Status:                simulatedStatusText[status],
+		StatusCode:            status,
+		SubTotal:              100000,
+		Total:                 104000,
+		Amount:                100000,
+		Fee:                   4000,
+		CreatedAt:             NullTime{Time: now.Add(-5 * time.Minute), Valid: true},
+		ExpiredAt:             NullTime{Time: now.Add(24 * time.Hour), Valid: true},
+		SettlementStatus:      "unsettled",
+		TransactionStatusCode: int(status),
+		Via:                   string(VirtualAccount),
+		Channel:               string(BCA),
+		PaymentNo:             "8800000000000000",
+		BuyerName:             "buyer",
+		BuyerEmail:            "buyer@example.com",
+		BuyerPhone:            "08123456789",
+	}
+	if status == Success || status == SuccessUnsettle {
+		cb.PaidAt = NullTime{Time: now, Valid: true}
+		cb.PaidOff = cb.Amount
+	}
+	return cb
+}
+
+// SimulateCallback posts cb to notifyURL the way iPaymu does, as a form or as JSON,
+// and returns the HTTP status the handler replied with.
+func SimulateCallback(ctx context.Context, notifyURL string, cb RequestCallBack, format CallbackFormat) (int, error) {
+	var body []byte
+	var contentType string
+	switch format {
+	case JSONCallback:
+		var err error
+		if body, err = json.Marshal(cb); err != nil {
+			return 0, err
+		}
+		contentType = "application/json"
+	case FormCallback, "":
+		values, err := cb.Values()
+		if err != nil {
+			return 0, err
+		}
+		body = []byte(values.Encode())
+		contentType = "application/x-www-form-urlencoded"
+	default:
+		return 0, fmt.Errorf("unknown callback format %q", format)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, notifyURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := (&http.Client{Timeout: defHTTPTimeout}).Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
+
+// SimulateSequence posts the callbacks of a successful payment to notifyURL: pending, then paid
+// but unsettled, then settled, waiting interval between them. It stops at the first callback
+// that is not answered with 200 OK.
+func SimulateSequence(ctx context.Context, notifyURL string, trxID int, referenceID string, format CallbackFormat, interval time.Duration) error {
+	pending := NewSimulatedCallback(trxID, referenceID, Pending)
+	paid := NewSimulatedCallback(trxID, referenceID, Success)
+	settled := paid
+	settled.SettlementStatus = "settled"
+
+	for i, cb := range []RequestCallBack{pending, paid, settled} {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(interval):
+			}
+		}
+
+		code, err := SimulateCallback(ctx, notifyURL, cb, format)
+		if err != nil {
+			return err
+		}
+		if code != http.StatusOK {
+			return fmt.Errorf("callback %s (settlement %s) answered with status %d", cb.Status, cb.SettlementStatus, code)
+		}
+	}
+	return nil
+}
+
+// Values encodes the callback as form values, the format iPaymu uses by default.
+func (c RequestCallBack) Values() (url.Values, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for key, raw := range c.Extra {
+		fields[key] = raw
+	}
+
+	values := make(url.Values, len(fields))
+	for key, raw := range fields {
+		if len(raw) > 0 && (raw[0] == '{' || raw[0] == '[') {
+			values.Set(key, string(raw))
+			continue
+		}
+		v, err := flexString(raw)
+		if err != nil {
+			return nil, err
+		}
+		values.Set(key, strings.TrimSpace(v))
+	}
+	return values, nil
+}
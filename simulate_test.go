@@ -0,0 +1,53 @@
+package ipaymu_go_api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestSimulateSequence(t *testing.T) {
+	for _, format := range []CallbackFormat{FormCallback, JSONCallback} {
+		t.Run(string(format), func(t *testing.T) {
+			var mu sync.Mutex
+			var calls []string
+			record := func(name string) CallbackFunc {
+				return func(r *http.Request, event *CallbackEvent) error {
+					mu.Lock()
+					defer mu.Unlock()
+					if event.Callback.TrxID != 96748 || event.Callback.ReferenceID != "trx-123" {
+						t.Errorf("callback = %+v, want trx 96748 ref trx-123", event.Callback)
+					}
+					calls = append(calls, name)
+					return nil
+				}
+			}
+
+			d := NewCallbackDispatcher()
+			d.OnPending(record("pending"))
+			d.OnSuccess(record("success"))
+			d.OnSettled(record("settled"))
+			h := NewCallbackHandler(d.Handle)
+			h.Dedup = NewMemoryDedupStore()
+
+			srv := httptest.NewServer(h)
+			defer srv.Close()
+
+			if err := SimulateSequence(context.Background(), srv.URL, 96748, "trx-123", format, 0); err != nil {
+				t.Fatalf("SimulateSequence() error = %v", err)
+			}
+			// Replaying the sequence must not reach the handlers again.
+			if err := SimulateSequence(context.Background(), srv.URL, 96748, "trx-123", format, 0); err != nil {
+				t.Fatalf("SimulateSequence() replay error = %v", err)
+			}
+
+			want := []string{"pending", "success", "settled"}
+			if !reflect.DeepEqual(calls, want) {
+				t.Errorf("calls = %v, want %v", calls, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package ipaymu_go_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+type SplitType string
+
+const (
+	SplitFixed   SplitType = "fixed"
+	SplitPercent SplitType = "percent"
+)
+
+// SplitRule assigns part of a payment to a member account, either as a fixed amount or as a percentage of the payment amount.
+// Amount is used for SplitFixed and Percent for SplitPercent; both are sent to the API as "value".
+type SplitRule struct {
+	Account string
+	Type    SplitType
+	Amount  Money
+	Percent float64
+}
+
+// splitRuleJSON is the wire format of SplitRule.
+type splitRuleJSON struct {
+	Account string          `json:"account"`
+	Type    SplitType       `json:"type"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// MarshalJSON implements json.Marshaler, sending Amount or Percent as the value depending on Type.
+func (s SplitRule) MarshalJSON() ([]byte, error) {
+	var value interface{} = s.Amount
+	if s.Type == SplitPercent {
+		value = s.Percent
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(splitRuleJSON{Account: s.Account, Type: s.Type, Value: raw})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, reading the value into Amount or Percent depending on Type.
+func (s *SplitRule) UnmarshalJSON(data []byte) error {
+	var rule splitRuleJSON
+	if err := json.Unmarshal(data, &rule); err != nil {
+		return err
+	}
+	*s = SplitRule{Account: rule.Account, Type: rule.Type}
+	if len(rule.Value) == 0 {
+		return nil
+	}
+	if rule.Type == SplitPercent {
+		return json.Unmarshal(rule.Value, &s.Percent)
+	}
+	return json.Unmarshal(rule.Value, &s.Amount)
+}
+
+// ErrInvalidSplit is returned when the split rules of a payment request cannot be applied to its amount.
+var ErrInvalidSplit = errors.New("invalid split rules")
+
+// AmountOf returns the part of amount that the rule assigns to its account.
+// Percentages are rounded down, so the remainder of a rupiah stays with the merchant.
+func (s SplitRule) AmountOf(amount Money) Money {
+	if s.Type == SplitPercent {
+		return amount.Percent(s.Percent, RoundDown)
+	}
+	return s.Amount
+}
+
+// ValidateSplit checks that the split rules can be applied to a payment of the given amount.
+//
+// Every rule must have an account, a known type, and a positive Amount or Percent; percentages may not exceed 100.
+// The sum of all split amounts may not exceed amount, the remainder stays with the merchant.
+// The returned error wraps ErrInvalidSplit.
+func ValidateSplit(rules []SplitRule, amount Money) error {
+	var total Money
+	for i, rule := range rules {
+		if rule.Account == "" {
+			return fmt.Errorf("%w: rule %d has no account", ErrInvalidSplit, i)
+		}
+		switch rule.Type {
+		case SplitFixed:
+			if rule.Amount <= 0 {
+				return fmt.Errorf("%w: rule %d has a non-positive amount", ErrInvalidSplit, i)
+			}
+		case SplitPercent:
+			if math.IsNaN(rule.Percent) || math.IsInf(rule.Percent, 0) {
+				return fmt.Errorf("%w: rule %d has an invalid percent", ErrInvalidSplit, i)
+			}
+			if rule.Percent <= 0 {
+				return fmt.Errorf("%w: rule %d has a non-positive percent", ErrInvalidSplit, i)
+			}
+			if rule.Percent > 100 {
+				return fmt.Errorf("%w: rule %d exceeds 100 percent", ErrInvalidSplit, i)
+			}
+		default:
+			return fmt.Errorf("%w: rule %d has unknown type %q", ErrInvalidSplit, i, rule.Type)
+		}
+		total = total.Add(rule.AmountOf(amount))
+	}
+
+	if total > amount {
+		return fmt.Errorf("%w: splits total %s exceeds amount %s", ErrInvalidSplit, total, amount)
+	}
+
+	return nil
+}
+
+// SplitMutations lists the commission mutations that were created for the splits of a payment.
+//
+// transactionID: The TransactionId of the parent payment.
+// request: The history filter to search in, typically a date range around the payment. All pages are read.
+//
+// Commission mutations are linked to the parent payment through their RelatedId.
+func (c *Client) SplitMutations(transactionID int, request RequestTransactionHistory) (trx []Transaction, err error) {
+	err = NewHistoryPager(c, request).Each(context.Background(), func(t Transaction) error {
+		if t.RelatedId.Int() == transactionID && t.Type.Int() == int(Commission) {
+			trx = append(trx, t)
+		}
+		return nil
+	})
+	return
+}
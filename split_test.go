@@ -0,0 +1,121 @@
+package ipaymu_go_api
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestValidateSplit(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []SplitRule
+		amount  Money
+		wantErr bool
+	}{
+		{
+			name:    "no rules",
+			rules:   nil,
+			amount:  100000,
+			wantErr: false,
+		},
+		{
+			name: "fixed and percent within amount",
+			rules: []SplitRule{
+				{Account: "1179000001", Type: SplitFixed, Amount: 80000},
+				{Account: "1179000002", Type: SplitPercent, Percent: 10},
+			},
+			amount:  100000,
+			wantErr: false,
+		},
+		{
+			name: "percent sums to whole amount",
+			rules: []SplitRule{
+				{Account: "1179000001", Type: SplitPercent, Percent: 95},
+				{Account: "1179000002", Type: SplitPercent, Percent: 5},
+			},
+			amount:  100000,
+			wantErr: false,
+		},
+		{
+			name: "splits exceed amount",
+			rules: []SplitRule{
+				{Account: "1179000001", Type: SplitFixed, Amount: 95000},
+				{Account: "1179000002", Type: SplitPercent, Percent: 10},
+			},
+			amount:  100000,
+			wantErr: true,
+		},
+		{
+			name:    "percent over 100",
+			rules:   []SplitRule{{Account: "1179000001", Type: SplitPercent, Percent: 120}},
+			amount:  100000,
+			wantErr: true,
+		},
+		{
+			name:    "fixed without amount",
+			rules:   []SplitRule{{Account: "1179000001", Type: SplitFixed, Percent: 10}},
+			amount:  100000,
+			wantErr: true,
+		},
+		{
+			name:    "percent NaN",
+			rules:   []SplitRule{{Account: "1179000001", Type: SplitPercent, Percent: math.NaN()}},
+			amount:  100000,
+			wantErr: true,
+		},
+		{
+			name:    "percent infinite",
+			rules:   []SplitRule{{Account: "1179000001", Type: SplitPercent, Percent: math.Inf(1)}},
+			amount:  100000,
+			wantErr: true,
+		},
+		{
+			name:    "missing account",
+			rules:   []SplitRule{{Type: SplitFixed, Amount: 1000}},
+			amount:  100000,
+			wantErr: true,
+		},
+		{
+			name:    "unknown type",
+			rules:   []SplitRule{{Account: "1179000001", Type: "share", Amount: 1000}},
+			amount:  100000,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSplit(tt.rules, tt.amount)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSplit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidSplit) {
+				t.Errorf("ValidateSplit() error = %v, want wrapped ErrInvalidSplit", err)
+			}
+		})
+	}
+}
+
+func TestSplitRule_JSON(t *testing.T) {
+	request := NewRequestRedirect()
+	request.AddSplitFixed("1179000001", 25000)
+	request.AddSplitPercent("1179000002", 2.5)
+
+	body, err := json.Marshal(request.Split)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"account":"1179000001","type":"fixed","value":25000},{"account":"1179000002","type":"percent","value":2.5}]`
+	if string(body) != want {
+		t.Errorf("Marshal() = %s, want %s", body, want)
+	}
+
+	var got []SplitRule
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(got) != 2 || got[0] != request.Split[0] || got[1] != request.Split[1] {
+		t.Errorf("Unmarshal() = %+v, want %+v", got, request.Split)
+	}
+}
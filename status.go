@@ -0,0 +1,156 @@
+package ipaymu_go_api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var paymentStatusNames = map[PaymentStatus]string{
+	Expired:         "expired",
+	Pending:         "pending",
+	Success:         "success",
+	Cancel:          "cancel",
+	Refund:          "refund",
+	Error:           "error",
+	Failed:          "failed",
+	SuccessUnsettle: "success_unsettle",
+	Escrow:          "escrow",
+}
+
+// paymentStatusTransitions lists the statuses each status can move to. Statuses without
+// an entry are terminal.
+var paymentStatusTransitions = map[PaymentStatus][]PaymentStatus{
+	Pending:         {Expired, Success, Cancel, Error, Failed, SuccessUnsettle, Escrow},
+	SuccessUnsettle: {Success, Refund},
+	Success:         {Refund},
+	Escrow:          {Success, SuccessUnsettle, Refund},
+}
+
+// ErrInvalidTransition is returned when a transaction moves between two statuses that cannot follow each other.
+var ErrInvalidTransition = errors.New("invalid payment status transition")
+
+// String returns the name of the status, such as "success" or "expired".
+func (s PaymentStatus) String() string {
+	if name, ok := paymentStatusNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("PaymentStatus(%d)", int8(s))
+}
+
+// IsTerminal reports whether the status is final: expired, cancelled, refunded, or failed.
+// Paid statuses are not terminal since they can still be refunded.
+func (s PaymentStatus) IsTerminal() bool {
+	_, ok := paymentStatusNames[s]
+	return ok && len(paymentStatusTransitions[s]) == 0
+}
+
+// IsPaid reports whether the buyer has paid: Success, SuccessUnsettle, or Escrow.
+func (s PaymentStatus) IsPaid() bool {
+	return s == Success || s == SuccessUnsettle || s == Escrow
+}
+
+// CanTransition reports whether a transaction can move from s to next. Staying in the same status is allowed.
+func (s PaymentStatus) CanTransition(next PaymentStatus) bool {
+	if s == next {
+		return true
+	}
+	for _, allowed := range paymentStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition returns an error wrapping ErrInvalidTransition when a transaction cannot move from one status to the other.
+func ValidateTransition(from, to PaymentStatus) error {
+	if !from.CanTransition(to) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, from, to)
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, encoding the status as its numeric code.
+func (s PaymentStatus) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Itoa(int(s))), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts the numeric code, as a number or
+// a string, and the status names accepted by ParsePaymentStatus.
+func (s *PaymentStatus) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	raw, err := flexString(data)
+	if err != nil {
+		return err
+	}
+	status, err := ParsePaymentStatus(raw)
+	if err != nil {
+		return err
+	}
+	*s = status
+	return nil
+}
+
+// statusText maps the status texts used by iPaymu, in Indonesian and English, to their PaymentStatus.
+var statusText = map[string]PaymentStatus{
+	"expired":          Expired,
+	"pending":          Pending,
+	"berhasil":         Success,
+	"success":          Success,
+	"batal":            Cancel,
+	"cancel":           Cancel,
+	"refund":           Refund,
+	"error":            Error,
+	"gagal":            Failed,
+	"failed":           Failed,
+	"success_unsettle": SuccessUnsettle,
+	"escrow":           Escrow,
+}
+
+// ParsePaymentStatus parses a status code such as "1" or a status text such as "berhasil" or "success".
+func ParsePaymentStatus(s string) (PaymentStatus, error) {
+	s = strings.TrimSpace(s)
+	if code, err := strconv.ParseInt(s, 10, 8); err == nil {
+		return PaymentStatus(code), nil
+	}
+	if status, ok := statusText[strings.ToLower(s)]; ok {
+		return status, nil
+	}
+	return 0, fmt.Errorf("unknown payment status %q", s)
+}
+
+// StatusTracker remembers the last status seen for each transaction and validates every new
+// status against it. It is safe for concurrent use and keeps its state in memory.
+type StatusTracker struct {
+	mu       sync.Mutex
+	statuses map[int]PaymentStatus
+}
+
+// NewStatusTracker creates an empty StatusTracker.
+func NewStatusTracker() *StatusTracker {
+	return &StatusTracker{
+		statuses: make(map[int]PaymentStatus),
+	}
+}
+
+// Observe records status for the transaction. When the move from the previously recorded
+// status is invalid, it returns an error wrapping ErrInvalidTransition and keeps the previous status.
+func (t *StatusTracker) Observe(transactionID int, status PaymentStatus) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if previous, ok := t.statuses[transactionID]; ok {
+		if err := ValidateTransition(previous, status); err != nil {
+			return fmt.Errorf("transaction %d: %w", transactionID, err)
+		}
+	}
+	t.statuses[transactionID] = status
+	return nil
+}
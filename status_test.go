@@ -0,0 +1,67 @@
+package ipaymu_go_api
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestValidateTransition(t *testing.T) {
+	tests := []struct {
+		from    PaymentStatus
+		to      PaymentStatus
+		wantErr bool
+	}{
+		{from: Pending, to: Success, wantErr: false},
+		{from: Pending, to: Expired, wantErr: false},
+		{from: SuccessUnsettle, to: Success, wantErr: false},
+		{from: Success, to: Refund, wantErr: false},
+		{from: Success, to: Success, wantErr: false},
+		{from: Expired, to: Success, wantErr: true},
+		{from: Success, to: Pending, wantErr: true},
+		{from: Refund, to: Success, wantErr: true},
+		{from: Failed, to: Pending, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from.String()+"_to_"+tt.to.String(), func(t *testing.T) {
+			err := ValidateTransition(tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTransition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidTransition) {
+				t.Errorf("ValidateTransition() error = %v, want wrapped ErrInvalidTransition", err)
+			}
+		})
+	}
+}
+
+func TestPaymentStatus_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		data    string
+		want    PaymentStatus
+		wantErr bool
+	}{
+		{data: `1`, want: Success},
+		{data: `"-2"`, want: Expired},
+		{data: `"berhasil"`, want: Success},
+		{data: `"success_unsettle"`, want: SuccessUnsettle},
+		{data: `"paid?"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			var got PaymentStatus
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("UnmarshalJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	data, _ := json.Marshal(Expired)
+	if string(data) != "-2" {
+		t.Errorf("MarshalJSON() = %s, want -2", data)
+	}
+}
@@ -18,13 +18,13 @@ import (
 func (c *Client) CheckTransaction(transactionID int) (res ResponseCheck, err error) {
     uri, _ := url.Parse(fmt.Sprintf("%s/api/v2/transaction", c.EnvApi))
     jsonBody, _ := json.Marshal(map[string]int{"transactionId": transactionID})
-    signature := fmt.Sprintf("%s", GenerateSignature(string(jsonBody), "POST", *c))
+    signature := fmt.Sprintf("%s", c.signature(jsonBody))
     api, err := c.CallApi(uri, signature, jsonBody)
     if err != nil {
         return res, err
     }
 
-    err = json.Unmarshal(api, &res)
+    err = c.decodeResponse(uri, api, &res)
     if err != nil {
         return res, err
     }
@@ -46,16 +46,21 @@ func (c *Client) CheckTransaction(transactionID int) (res ResponseCheck, err err
 // err: An error if any occurred during the API call or response parsing.
 //
 // Note: If the API call is successful and the transaction history status is not 200, an error will be returned with the corresponding message.
+// The request is checked with Validate first; an invalid request is not sent.
 func (c *Client) HistoryTransaction(request RequestTransactionHistory) (res ResponseTransaction, err error) {
+    if err = request.Validate(); err != nil {
+        return
+    }
+
     uri, _ := url.Parse(fmt.Sprintf("%s/api/v2/history", c.EnvApi))
     jsonBody, _ := json.Marshal(request)
-    signature := fmt.Sprintf("%s", GenerateSignature(string(jsonBody), "POST", *c))
+    signature := fmt.Sprintf("%s", c.signature(jsonBody))
     api, err := c.CallApi(uri, signature, jsonBody)
     if err != nil {
         return
     }
 
-    err = json.Unmarshal(api, &res)
+    err = c.decodeResponse(uri, api, &res)
     if err != nil {
         return
     }
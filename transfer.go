@@ -0,0 +1,68 @@
+package ipaymu_go_api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// TransferBalance moves funds between the merchant account and a member account.
+//
+// Parameters:
+//   - request: A RequestTransferBalance struct containing the sender, receiver, and amount. When Sender is empty,
+//     the client's virtual account is used.
+//
+// Return values:
+// - res: A ResponseTransfer struct containing the transaction created for the transfer.
+// - err: An error if any occurred during the API call or response parsing.
+//
+// If the API call fails or the status code is not 200, the function returns an error with the corresponding message.
+func (c *Client) TransferBalance(request RequestTransferBalance) (res ResponseTransfer, err error) {
+	if request.Sender == "" {
+		request.Sender = c.VirtualAccount
+	}
+
+	uri, _ := url.Parse(fmt.Sprintf("%s/api/v2/transferva", c.EnvApi))
+	jsonBody, _ := json.Marshal(request)
+	signature := c.signature(jsonBody)
+	api, err := c.CallApi(uri, signature, jsonBody)
+	if err != nil {
+		return
+	}
+
+	err = c.decodeResponse(uri, api, &res)
+	if err != nil {
+		return
+	}
+
+	if res.Status != 200 {
+		return res, fmt.Errorf("%s", res.Message)
+	}
+
+	return
+}
+
+// TransferMutation looks up the balance mutation recorded in the transaction history for a transfer.
+//
+// transactionID: The TransactionId returned by TransferBalance.
+//
+// Returns the matching Transaction, or an error if the history call fails or the transfer is not found.
+func (c *Client) TransferMutation(transactionID int) (trx Transaction, err error) {
+	bulkID := strconv.Itoa(transactionID)
+	request := NewRequestTransactionHistory()
+	request.BulkId = &bulkID
+
+	res, err := c.HistoryTransaction(*request)
+	if err != nil {
+		return
+	}
+
+	for _, t := range res.Data.Transaction {
+		if t.TransactionId.Int() == transactionID && t.Type.Int() == int(MoveBalance) {
+			return t, nil
+		}
+	}
+
+	return trx, fmt.Errorf("transfer mutation %d not found", transactionID)
+}
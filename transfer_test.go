@@ -0,0 +1,94 @@
+package ipaymu_go_api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_TransferBalance(t *testing.T) {
+	var got RequestTransferBalance
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/transferva" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"Status":200,"Data":{"TransactionId":5001,"Sender":"1179000899","Receiver":"1179000001","Amount":25000,"Fee":0},"Message":"success"}`))
+	}))
+	defer srv.Close()
+
+	cl := &Client{}
+	cl.AssignCredential("api-key", "1179000899", EnvironmentType(srv.URL))
+
+	tests := []struct {
+		name       string
+		sender     string
+		wantSender string
+	}{
+		{
+			name:       "defaults to virtual account",
+			sender:     "",
+			wantSender: "1179000899",
+		},
+		{
+			name:       "keeps explicit sender",
+			sender:     "1179000002",
+			wantSender: "1179000002",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := NewRequestTransferBalance("1179000001", 25000)
+			request.Sender = tt.sender
+			res, err := cl.TransferBalance(*request)
+			if err != nil {
+				t.Fatalf("TransferBalance() error = %v", err)
+			}
+			if got.Sender != tt.wantSender || got.Receiver != "1179000001" || got.Amount != 25000 {
+				t.Errorf("TransferBalance() sent %+v, want sender %s", got, tt.wantSender)
+			}
+			if res.Data.TransactionId != 5001 {
+				t.Errorf("TransferBalance() TransactionId = %d, want 5001", res.Data.TransactionId)
+			}
+		})
+	}
+}
+
+func TestClient_TransferMutation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The history holds a payment and the transfer sharing the same ID.
+		w.Write([]byte(`{"Status":200,"Success":true,"Message":"success","Data":{"Transaction":[
+			{"TransactionId":5001,"Type":7,"Amount":100000},
+			{"TransactionId":5001,"Type":20,"Amount":25000},
+			{"TransactionId":5002,"Type":20,"Amount":10000}
+		]}}`))
+	}))
+	defer srv.Close()
+
+	cl := &Client{}
+	cl.AssignCredential("api-key", "1179000899", EnvironmentType(srv.URL))
+
+	tests := []struct {
+		name       string
+		id         int
+		wantAmount Money
+		wantErr    bool
+	}{
+		{name: "move balance row", id: 5001, wantAmount: 25000},
+		{name: "other transfer", id: 5002, wantAmount: 10000},
+		{name: "not found", id: 5003, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx, err := cl.TransferMutation(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TransferMutation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (trx.Type.Int() != int(MoveBalance) || trx.Amount != tt.wantAmount) {
+				t.Errorf("TransferMutation() = %+v, want MoveBalance of %s", trx, tt.wantAmount)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package ipaymu_go_api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jakarta is the time zone iPaymu uses for every timestamp it sends.
+var jakarta = loadJakarta()
+
+func loadJakarta() *time.Location {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		return time.FixedZone("WIB", 7*60*60)
+	}
+	return loc
+}
+
+// timeLayouts are the timestamp formats seen in iPaymu payloads, tried in order.
+var timeLayouts = []string{
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// NullTime is a timestamp sent by iPaymu, interpreted in Asia/Jakarta.
+//
+// Valid is false when the value is null, empty, a zero date such as "0000-00-00 00:00:00",
+// or in a format that is not recognized. Raw always holds the string as it was received.
+type NullTime struct {
+	Time  time.Time
+	Valid bool
+	Raw   string
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *NullTime) UnmarshalJSON(data []byte) error {
+	*t = NullTime{}
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("invalid time %s: %w", data, err)
+	}
+
+	// A timestamp in an unknown format must not fail the whole response, so it is only kept in Raw.
+	*t, _ = parseNullTime(raw)
+	return nil
+}
+
+// Ptr returns a pointer to the time, or nil when the timestamp is not valid.
+func (t NullTime) Ptr() *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	tm := t.Time
+	return &tm
+}
+
+// String returns the raw timestamp as received.
+func (t NullTime) String() string {
+	return t.Raw
+}
+
+// parseNullTime parses a timestamp string as sent by iPaymu.
+func parseNullTime(raw string) (NullTime, error) {
+	t := NullTime{Raw: raw}
+
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.HasPrefix(raw, "0000-00-00") {
+		return t, nil
+	}
+
+	for _, layout := range timeLayouts {
+		if parsed, err := time.ParseInLocation(layout, raw, jakarta); err == nil {
+			t.Time = parsed.In(jakarta)
+			t.Valid = true
+			return t, nil
+		}
+	}
+
+	return t, fmt.Errorf("invalid time %q", raw)
+}
+
+// MarshalJSON implements json.Marshaler. The raw string is written back as received,
+// so that a decoded payload can be encoded again without changes.
+func (t NullTime) MarshalJSON() ([]byte, error) {
+	if t.Raw != "" {
+		return json.Marshal(t.Raw)
+	}
+	if !t.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.Time.In(jakarta).Format(timeLayouts[0]))
+}
+
+// flexString decodes a JSON string, number, or boolean into a string; null decodes to "".
+func flexString(raw json.RawMessage) (string, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return "", nil
+	}
+	if raw[0] == '"' {
+		var s string
+		err := json.Unmarshal(raw, &s)
+		return s, err
+	}
+	if raw[0] == '{' || raw[0] == '[' {
+		return "", fmt.Errorf("cannot decode %s as string", raw)
+	}
+	return string(raw), nil
+}
+
+// flexInt decodes a JSON number or numeric string into an int64; null and "" decode to 0.
+func flexInt(raw json.RawMessage) (int64, error) {
+	s, err := flexString(raw)
+	if err != nil || s == "" {
+		return 0, err
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != float64(int64(f)) {
+		return 0, fmt.Errorf("cannot decode %s as integer", raw)
+	}
+	return int64(f), nil
+}
+
+// flexBool decodes a JSON boolean, number, or string such as "1", "0", "true" or "false" into a bool.
+func flexBool(raw json.RawMessage) (bool, error) {
+	s, err := flexString(raw)
+	if err != nil || s == "" {
+		return false, err
+	}
+	b, err := strconv.ParseBool(strings.ToLower(s))
+	if err != nil {
+		return false, fmt.Errorf("cannot decode %s as boolean", raw)
+	}
+	return b, nil
+}
+
+// FlexString is a string field that iPaymu sends as a string, a number, or null
+// depending on the endpoint and environment. Numbers are kept as written and null decodes to "".
+type FlexString string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *FlexString) UnmarshalJSON(data []byte) error {
+	v, err := flexString(data)
+	if err != nil {
+		return err
+	}
+	*s = FlexString(v)
+	return nil
+}
+
+// String returns the value as a string.
+func (s FlexString) String() string {
+	return string(s)
+}
+
+// FlexInt is an integer field that iPaymu sends as a number, a numeric string, or null.
+// Null and the empty string decode to 0.
+type FlexInt int
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *FlexInt) UnmarshalJSON(data []byte) error {
+	v, err := flexInt(data)
+	if err != nil {
+		return err
+	}
+	*n = FlexInt(v)
+	return nil
+}
+
+// Int returns the value as an int.
+func (n FlexInt) Int() int {
+	return int(n)
+}
+
+// FlexBool is a boolean field that iPaymu sends as a boolean, 0 or 1, or a string such as "true" or "1".
+// Null and the empty string decode to false.
+type FlexBool bool
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *FlexBool) UnmarshalJSON(data []byte) error {
+	v, err := flexBool(data)
+	if err != nil {
+		return err
+	}
+	*b = FlexBool(v)
+	return nil
+}
@@ -0,0 +1,77 @@
+package ipaymu_go_api
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestNullTime_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		wantValid bool
+		wantTime  time.Time
+		wantRaw   string
+	}{
+		{
+			name:      "jakarta local time",
+			data:      `"2024-05-01 10:00:00"`,
+			wantValid: true,
+			wantTime:  time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC),
+			wantRaw:   "2024-05-01 10:00:00",
+		},
+		{
+			name:      "rfc3339 in utc",
+			data:      `"2024-05-01T03:00:00Z"`,
+			wantValid: true,
+			wantTime:  time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC),
+			wantRaw:   "2024-05-01T03:00:00Z",
+		},
+		{
+			name:      "null",
+			data:      `null`,
+			wantValid: false,
+		},
+		{
+			name:      "empty string",
+			data:      `""`,
+			wantValid: false,
+		},
+		{
+			name:      "zero date",
+			data:      `"0000-00-00 00:00:00"`,
+			wantValid: false,
+			wantRaw:   "0000-00-00 00:00:00",
+		},
+		{
+			name:      "unknown format",
+			data:      `"yesterday"`,
+			wantValid: false,
+			wantRaw:   "yesterday",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var trx Transaction
+			data := `{"TransactionId":1,"CreatedDate":"2024-05-01 09:00:00","SuccessDate":` + tt.data + `}`
+			if err := json.Unmarshal([]byte(data), &trx); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+
+			got := trx.SuccessDate
+			if got.Valid != tt.wantValid || got.Raw != tt.wantRaw {
+				t.Errorf("SuccessDate = %+v, want valid %v raw %q", got, tt.wantValid, tt.wantRaw)
+			}
+			if tt.wantValid && (!got.Time.Equal(tt.wantTime) || got.Time.Location() != jakarta) {
+				t.Errorf("SuccessDate time = %v, want %v in Asia/Jakarta", got.Time, tt.wantTime)
+			}
+			if !tt.wantValid && got.Ptr() != nil {
+				t.Errorf("SuccessDate.Ptr() = %v, want nil", got.Ptr())
+			}
+			if !trx.CreatedDate.Valid {
+				t.Errorf("CreatedDate = %+v, want valid", trx.CreatedDate)
+			}
+		})
+	}
+}
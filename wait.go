@@ -0,0 +1,99 @@
+package ipaymu_go_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTransactionExpired is returned by WaitForTransaction when the transaction expires while still pending.
+var ErrTransactionExpired = errors.New("transaction expired before reaching a final status")
+
+// WaitOptions configures WaitForTransaction.
+type WaitOptions struct {
+	// Interval is the wait before the second poll, 2 seconds when zero. It grows by
+	// Multiplier (1.5 when zero) after every poll, up to MaxInterval (30 seconds when zero).
+	Interval    time.Duration
+	MaxInterval time.Duration
+	Multiplier  float64
+
+	// OnInvalidTransition, when set, is called when two polls report statuses that cannot
+	// follow each other, such as Expired and then Success.
+	OnInvalidTransition func(err error)
+
+	// Updates, when set, receives the transaction every time its status changes, including
+	// the first poll. It is not closed by WaitForTransaction.
+	Updates chan<- ResponseCheck
+}
+
+// WaitForTransaction polls CheckTransaction until the transaction is paid or reaches a terminal
+// status, and returns the last ResponseCheck.
+//
+// Polling stops with ErrTransactionExpired once the expiry date of the transaction has passed
+// and a final poll still reports it pending, and with the context error when ctx is done.
+// Failed polls are retried; the last poll error is returned when ctx is done before any poll succeeded.
+func (c *Client) WaitForTransaction(ctx context.Context, transactionID int, opts WaitOptions) (res ResponseCheck, err error) {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	maxInterval := opts.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = 30 * time.Second
+	}
+	multiplier := opts.Multiplier
+	if multiplier <= 1 {
+		multiplier = 1.5
+	}
+
+	var polled bool
+	var lastStatus PaymentStatus
+	for {
+		check, checkErr := c.CheckTransaction(transactionID)
+		if checkErr == nil {
+			status := check.Data.Status
+			if polled && opts.OnInvalidTransition != nil {
+				if err := ValidateTransition(lastStatus, status); err != nil {
+					opts.OnInvalidTransition(fmt.Errorf("transaction %d: %w", transactionID, err))
+				}
+			}
+			if !polled || status != lastStatus {
+				if opts.Updates != nil {
+					select {
+					case opts.Updates <- check:
+					case <-ctx.Done():
+						return check, ctx.Err()
+					}
+				}
+			}
+			res, polled, lastStatus = check, true, status
+
+			if status.IsPaid() || status.IsTerminal() {
+				return res, nil
+			}
+			if expiry := check.Data.ExpiredDate; expiry.Valid && time.Now().After(expiry.Time) {
+				return res, ErrTransactionExpired
+			}
+		} else if !polled {
+			err = checkErr
+		}
+
+		select {
+		case <-ctx.Done():
+			if polled {
+				return res, ctx.Err()
+			}
+			if err == nil {
+				err = ctx.Err()
+			}
+			return res, err
+		case <-time.After(interval):
+		}
+
+		interval = time.Duration(float64(interval) * multiplier)
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package ipaymu_go_api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_WaitForTransaction(t *testing.T) {
+	tests := []struct {
+		name        string
+		statuses    []PaymentStatus
+		expiredDate string
+		wantStatus  PaymentStatus
+		wantUpdates []PaymentStatus
+		wantErr     error
+	}{
+		{
+			name:        "pending then paid",
+			statuses:    []PaymentStatus{Pending, Pending, Success},
+			expiredDate: "2999-01-01 00:00:00",
+			wantStatus:  Success,
+			wantUpdates: []PaymentStatus{Pending, Success},
+		},
+		{
+			name:        "pending past expiry",
+			statuses:    []PaymentStatus{Pending},
+			expiredDate: "2000-01-01 00:00:00",
+			wantStatus:  Pending,
+			wantUpdates: []PaymentStatus{Pending},
+			wantErr:     ErrTransactionExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var polls int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				i := int(atomic.AddInt32(&polls, 1)) - 1
+				if i >= len(tt.statuses) {
+					i = len(tt.statuses) - 1
+				}
+				fmt.Fprintf(w, `{"Status":200,"Data":{"TransactionId":96748,"Status":%d,"ExpiredDate":%q}}`, tt.statuses[i], tt.expiredDate)
+			}))
+			defer srv.Close()
+
+			cl := &Client{}
+			cl.AssignCredential("api-key", "1179000899", EnvironmentType(srv.URL))
+
+			updates := make(chan ResponseCheck, 10)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			res, err := cl.WaitForTransaction(ctx, 96748, WaitOptions{Interval: time.Millisecond, Updates: updates})
+			if err != tt.wantErr {
+				t.Fatalf("WaitForTransaction() error = %v, want %v", err, tt.wantErr)
+			}
+			if res.Data.Status != tt.wantStatus {
+				t.Errorf("WaitForTransaction() status = %v, want %v", res.Data.Status, tt.wantStatus)
+			}
+
+			close(updates)
+			var got []PaymentStatus
+			for update := range updates {
+				got = append(got, update.Data.Status)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.wantUpdates) {
+				t.Errorf("WaitForTransaction() updates = %v, want %v", got, tt.wantUpdates)
+			}
+		})
+	}
+}
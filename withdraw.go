@@ -0,0 +1,207 @@
+package ipaymu_go_api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+)
+
+// ErrInsufficientBalance is returned by Withdraw when the requested amount exceeds the last known merchant balance.
+var ErrInsufficientBalance = errors.New("withdraw amount exceeds the last known balance")
+
+// balanceMu guards the balance fields of Client. Client holds no lock itself because
+// it is a plain value that callers copy, for example to pass it to GenerateSignature.
+var balanceMu sync.Mutex
+
+// knownBalance returns the last merchant balance seen by GetBalance or Withdraw, and whether there is one.
+func (c *Client) knownBalance() (Money, bool) {
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+	if c.lastBalance == nil {
+		return 0, false
+	}
+	return *c.lastBalance, true
+}
+
+// balanceGeneration returns the number of withdrawals recorded in the last known balance so far.
+func (c *Client) balanceGeneration() uint64 {
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+	return c.balanceGen
+}
+
+// setBalance records balance as the last known merchant balance, unless a withdrawal was recorded
+// since generation. The balance may then have been read before that withdrawal went through.
+func (c *Client) setBalance(balance Money, generation uint64) {
+	balanceMu.Lock()
+	if c.lastBalance == nil || c.balanceGen == generation {
+		c.lastBalance = &balance
+	}
+	balanceMu.Unlock()
+}
+
+// reserveBalance sets amount aside for a withdrawal when the last known balance, less the amounts
+// already set aside, covers it. It reports whether the amount was reserved.
+func (c *Client) reserveBalance(amount Money) bool {
+	balanceMu.Lock()
+	defer balanceMu.Unlock()
+	if c.lastBalance == nil || amount > c.lastBalance.Sub(c.reservedBalance) {
+		return false
+	}
+	c.reservedBalance = c.reservedBalance.Add(amount)
+	return true
+}
+
+// settleBalance releases the reservation of amount and subtracts spent from the last known balance.
+func (c *Client) settleBalance(amount, spent Money) {
+	balanceMu.Lock()
+	c.reservedBalance = c.reservedBalance.Sub(amount)
+	if c.lastBalance != nil && spent != 0 {
+		balance := c.lastBalance.Sub(spent)
+		c.lastBalance = &balance
+		c.balanceGen++
+	}
+	balanceMu.Unlock()
+}
+
+// ListBank retrieves the list of destination banks supported for withdrawal.
+//
+// Return values:
+// - res: A ResponseBankList struct containing the supported banks.
+// - err: An error if any occurred during the API call or response parsing.
+//
+// If the API call fails or the status code is not 200, the function returns an error with the corresponding message.
+func (c *Client) ListBank() (res ResponseBankList, err error) {
+	uri, _ := url.Parse(fmt.Sprintf("%s/api/v2/bank-list", c.EnvApi))
+	jsonBody, _ := json.Marshal(map[string]bool{"request": true})
+	signature := c.signature(jsonBody)
+	api, err := c.CallApi(uri, signature, jsonBody)
+	if err != nil {
+		return
+	}
+
+	err = c.decodeResponse(uri, api, &res)
+	if err != nil {
+		return
+	}
+
+	if res.Status != 200 {
+		return res, fmt.Errorf("%s", res.Message)
+	}
+
+	return
+}
+
+// CheckBankAccount validates a destination bank account and returns the name registered for it.
+//
+// Parameters:
+// - request: A RequestBankInquiry struct containing the bank code and account number to check.
+//
+// Return values:
+// - res: A ResponseBankInquiry struct containing the account holder name.
+// - err: An error if any occurred during the API call or response parsing.
+//
+// If the API call fails or the status code is not 200, the function returns an error with the corresponding message.
+func (c *Client) CheckBankAccount(request RequestBankInquiry) (res ResponseBankInquiry, err error) {
+	uri, _ := url.Parse(fmt.Sprintf("%s/api/v2/check-bank-account", c.EnvApi))
+	jsonBody, _ := json.Marshal(request)
+	signature := c.signature(jsonBody)
+	api, err := c.CallApi(uri, signature, jsonBody)
+	if err != nil {
+		return
+	}
+
+	err = c.decodeResponse(uri, api, &res)
+	if err != nil {
+		return
+	}
+
+	if res.Status != 200 {
+		return res, fmt.Errorf("%s", res.Message)
+	}
+
+	return
+}
+
+// Withdraw moves funds from the merchant balance to the given bank account.
+//
+// Parameters:
+// - request: A RequestWithdraw struct containing the amount and the destination bank account.
+//
+// Return values:
+// - res: A ResponseWithdraw struct containing the created withdrawal.
+// - err: An error if any occurred during the API call or response parsing.
+//
+// Before sending the request the amount is reserved against the last balance returned by GetBalance,
+// less the withdrawals still in flight, so concurrent calls cannot together exceed it. When no balance
+// is known yet, or the amount exceeds what is left, GetBalance is called once to refresh it. If the
+// amount still does not fit, ErrInsufficientBalance is returned and no request is sent. A failed
+// withdrawal gives its reservation back.
+func (c *Client) Withdraw(request RequestWithdraw) (res ResponseWithdraw, err error) {
+	if !c.reserveBalance(request.Amount) {
+		if _, err = c.GetBalance(); err != nil {
+			return
+		}
+		if !c.reserveBalance(request.Amount) {
+			return res, ErrInsufficientBalance
+		}
+	}
+	defer func() {
+		if err != nil {
+			c.settleBalance(request.Amount, 0)
+			return
+		}
+		c.settleBalance(request.Amount, res.Data.Amount.Add(res.Data.Fee))
+	}()
+
+	uri, _ := url.Parse(fmt.Sprintf("%s/api/v2/withdraw", c.EnvApi))
+	jsonBody, _ := json.Marshal(request)
+	signature := c.signature(jsonBody)
+	api, err := c.CallApi(uri, signature, jsonBody)
+	if err != nil {
+		return
+	}
+
+	err = c.decodeResponse(uri, api, &res)
+	if err != nil {
+		return
+	}
+
+	if res.Status != 200 {
+		return res, fmt.Errorf("%s", res.Message)
+	}
+
+	return
+}
+
+// CheckWithdraw retrieves the status of a withdrawal by its ID.
+//
+// withdrawID: The identifier returned in ResponseWithdraw when the withdrawal was created.
+//
+// Return values:
+// - res: A ResponseWithdraw struct containing the withdrawal details and status.
+// - err: An error if any occurred during the API call or response parsing.
+//
+// If the API call fails or the status code is not 200, the function returns an error with the corresponding message.
+func (c *Client) CheckWithdraw(withdrawID int) (res ResponseWithdraw, err error) {
+	uri, _ := url.Parse(fmt.Sprintf("%s/api/v2/withdraw-status", c.EnvApi))
+	jsonBody, _ := json.Marshal(map[string]int{"withdrawId": withdrawID})
+	signature := c.signature(jsonBody)
+	api, err := c.CallApi(uri, signature, jsonBody)
+	if err != nil {
+		return
+	}
+
+	err = c.decodeResponse(uri, api, &res)
+	if err != nil {
+		return
+	}
+
+	if res.Status != 200 {
+		return res, fmt.Errorf("%s", res.Message)
+	}
+
+	return
+}
@@ -0,0 +1,178 @@
+package ipaymu_go_api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_Withdraw(t *testing.T) {
+	var mu sync.Mutex
+	serverBalance := Money(50000)
+	balanceCalls, withdrawCalls := 0, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/api/v2/balance":
+			balanceCalls++
+			fmt.Fprintf(w, `{"Status":200,"Data":{"Va":"1179000899","MerchantBalance":%d,"MemberBalance":0},"Message":"success"}`, serverBalance)
+		case "/api/v2/withdraw":
+			withdrawCalls++
+			serverBalance -= 22500
+			w.Write([]byte(`{"Status":200,"Data":{"WithdrawId":1,"Amount":20000,"Fee":2500,"Status":0},"Message":"success"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	cl := &Client{}
+	cl.AssignCredential("api-key", "1179000899", EnvironmentType(srv.URL))
+
+	tests := []struct {
+		name             string
+		topUp            Money
+		amount           Money
+		wantErr          error
+		wantBalanceCalls int
+		wantCalled       bool
+	}{
+		{
+			name:             "exceeds balance",
+			amount:           60000,
+			wantErr:          ErrInsufficientBalance,
+			wantBalanceCalls: 1,
+			wantCalled:       false,
+		},
+		{
+			name:             "within balance",
+			amount:           20000,
+			wantErr:          nil,
+			wantBalanceCalls: 0,
+			wantCalled:       true,
+		},
+		{
+			name:             "exceeds balance after previous withdraw",
+			amount:           30000,
+			wantErr:          ErrInsufficientBalance,
+			wantBalanceCalls: 1,
+			wantCalled:       false,
+		},
+		{
+			name:             "balance topped up since last fetch",
+			topUp:            10000,
+			amount:           30000,
+			wantErr:          nil,
+			wantBalanceCalls: 1,
+			wantCalled:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			serverBalance += tt.topUp
+			balanceCalls, withdrawCalls = 0, 0
+			mu.Unlock()
+
+			_, err := cl.Withdraw(*NewRequestWithdraw(tt.amount, "bca", "1234567890"))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Withdraw() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if balanceCalls != tt.wantBalanceCalls {
+				t.Errorf("Withdraw() fetched balance %d times, want %d", balanceCalls, tt.wantBalanceCalls)
+			}
+			if (withdrawCalls > 0) != tt.wantCalled {
+				t.Errorf("Withdraw() called api = %v, want %v", withdrawCalls > 0, tt.wantCalled)
+			}
+		})
+	}
+}
+
+func TestClient_WithdrawConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	serverBalance := Money(50000)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/balance":
+			mu.Lock()
+			fmt.Fprintf(w, `{"Status":200,"Data":{"Va":"1179000899","MerchantBalance":%d,"MemberBalance":0},"Message":"success"}`, serverBalance)
+			mu.Unlock()
+		case "/api/v2/withdraw":
+			// The API does not check the balance itself, so every overdraft reaches it.
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			serverBalance -= 20000
+			mu.Unlock()
+			w.Write([]byte(`{"Status":200,"Data":{"WithdrawId":1,"Amount":20000,"Fee":0,"Status":0},"Message":"success"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	cl := &Client{}
+	cl.AssignCredential("api-key", "1179000899", EnvironmentType(srv.URL))
+	cl.GetBalance()
+
+	var wg sync.WaitGroup
+	var withdrawn int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := cl.Withdraw(*NewRequestWithdraw(20000, "bca", "1234567890"))
+			switch {
+			case err == nil:
+				atomic.AddInt32(&withdrawn, 1)
+			case !errors.Is(err, ErrInsufficientBalance):
+				t.Errorf("Withdraw() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if withdrawn == 0 || serverBalance < 0 {
+		t.Errorf("%d withdrawals left the balance at %s, want at least one and no overdraft", withdrawn, serverBalance)
+	}
+	if balance, _ := cl.knownBalance(); balance > serverBalance {
+		t.Errorf("lastBalance = %s, want at most %s", balance, serverBalance)
+	}
+}
+
+func TestClient_WithdrawFailed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/balance":
+			w.Write([]byte(`{"Status":200,"Data":{"Va":"1179000899","MerchantBalance":50000,"MemberBalance":0},"Message":"success"}`))
+		case "/api/v2/withdraw":
+			w.Write([]byte(`{"Status":400,"Message":"bank account not verified"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	cl := &Client{}
+	cl.AssignCredential("api-key", "1179000899", EnvironmentType(srv.URL))
+
+	for i := 0; i < 3; i++ {
+		_, err := cl.Withdraw(*NewRequestWithdraw(40000, "bca", "1234567890"))
+		if err == nil || errors.Is(err, ErrInsufficientBalance) {
+			t.Fatalf("Withdraw() error = %v, want the API error", err)
+		}
+	}
+	if balance, _ := cl.knownBalance(); balance != 50000 || cl.reservedBalance != 0 {
+		t.Errorf("balance = %s reserved %s, want Rp50.000 with nothing reserved", balance, cl.reservedBalance)
+	}
+}